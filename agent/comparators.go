@@ -0,0 +1,114 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/datar-psa/goeval/api"
+	"github.com/datar-psa/goeval/embedding"
+)
+
+// ArgumentComparator scores how well an actual tool argument matches the expected one, in [0.0, 1.0]
+type ArgumentComparator func(ctx context.Context, expected, actual any) (float64, error)
+
+// ExactArgument returns a comparator requiring JSON-equal values, so 1 and 1.0 are equal and object key order is ignored
+func ExactArgument() ArgumentComparator {
+	return func(ctx context.Context, expected, actual any) (float64, error) {
+		if jsonEqual(expected, actual) {
+			return 1.0, nil
+		}
+		return 0.0, nil
+	}
+}
+
+// NumericArgument returns a comparator accepting numbers (or numeric strings) within an absolute tolerance
+// Non-numeric values are compared exactly
+func NumericArgument(tolerance float64) ArgumentComparator {
+	return func(ctx context.Context, expected, actual any) (float64, error) {
+		exp, expOK := toFloat(expected)
+		act, actOK := toFloat(actual)
+		if !expOK || !actOK {
+			return ExactArgument()(ctx, expected, actual)
+		}
+		if math.Abs(exp-act) <= tolerance {
+			return 1.0, nil
+		}
+		return 0.0, nil
+	}
+}
+
+// EmbeddingArgument returns a comparator scoring values by the raw cosine similarity of their embeddings
+// Values whose cosine similarity reaches threshold (default: 0.9) score 1.0, others get the similarity
+// (floored at 0) as partial credit. Non-string values are compared as their JSON encoding
+func EmbeddingArgument(embedder api.Embedder, threshold float64) ArgumentComparator {
+	if threshold <= 0 {
+		threshold = 0.9
+	}
+	scorer := embedding.EmbeddingSimilarity(embedder, embedding.EmbeddingSimilarityOptions{})
+	return func(ctx context.Context, expected, actual any) (float64, error) {
+		if jsonEqual(expected, actual) {
+			return 1.0, nil
+		}
+		result := scorer.Score(ctx, api.ScoreInputs{Output: argumentText(actual), Expected: argumentText(expected)})
+		if result.Error != nil {
+			return 0, result.Error
+		}
+		// The scorer maps cosine similarity into [0, 1], which puts unrelated texts around 0.5 and above,
+		// so the threshold applies to the raw cosine similarity instead
+		similarity, ok := result.Metadata["cosine_similarity"].(float64)
+		if !ok {
+			return 0, fmt.Errorf("embedding similarity did not report cosine_similarity")
+		}
+		if similarity >= threshold {
+			return 1.0, nil
+		}
+		return math.Max(similarity, 0), nil
+	}
+}
+
+func jsonEqual(a, b any) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return bytes.Equal(aJSON, bJSON)
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// argumentText renders an argument value as text for embedding
+func argumentText(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(raw)
+}
@@ -0,0 +1,236 @@
+// Package agent provides scorers for tool-using agents: tool-call correctness and trajectory evaluation
+package agent
+
+import (
+	"context"
+	"sort"
+
+	"github.com/datar-psa/goeval/api"
+	"github.com/datar-psa/goeval/internal/assignment"
+)
+
+// ToolCallMatchOptions configures the ToolCallMatch scorer
+type ToolCallMatchOptions struct {
+	// OrderSensitive requires tool calls to appear in the expected order (default: any order)
+	OrderSensitive bool
+	// ArgumentSubset only checks arguments present in the expected call; extra arguments are not penalized
+	ArgumentSubset bool
+	// Comparators are per-argument comparators keyed by "tool.argument" or by "argument" for any tool
+	Comparators map[string]ArgumentComparator
+	// DefaultComparator is used for arguments without a specific comparator (default: ExactArgument)
+	DefaultComparator ArgumentComparator
+}
+
+// ToolCallResult reports how a single expected tool call was matched
+type ToolCallResult struct {
+	ExpectedIndex       int                `json:"expected_index"`
+	OutputIndex         int                `json:"output_index"`
+	ExpectedName        string             `json:"expected_name"`
+	OutputName          string             `json:"output_name"`
+	Score               float64            `json:"score"`
+	WrongTool           bool               `json:"wrong_tool,omitempty"`
+	ArgumentScores      map[string]float64 `json:"argument_scores,omitempty"`
+	MissingArguments    []string           `json:"missing_arguments,omitempty"`
+	ExtraArguments      []string           `json:"extra_arguments,omitempty"`
+	MismatchedArguments []string           `json:"mismatched_arguments,omitempty"`
+}
+
+// ToolCallMatch returns a scorer that compares ToolCalls against ExpectedToolCalls
+// Each call scores the fraction of matching arguments (0 for the wrong tool), and the total is
+// divided by the larger of the expected and produced call counts, so missing and extra calls cost credit
+func ToolCallMatch(opts ToolCallMatchOptions) api.Scorer {
+	return &toolCallMatchScorer{opts: opts}
+}
+
+type toolCallMatchScorer struct {
+	opts ToolCallMatchOptions
+}
+
+// pairingBonus makes the alignment prefer pairing calls over leaving them unmatched,
+// so a wrong tool in place of an expected one is reported as such rather than as missing plus extra
+const pairingBonus = 1e-9
+
+func (s *toolCallMatchScorer) Score(ctx context.Context, in api.ScoreInputs) api.Score {
+	result := api.Score{
+		Name:     "ToolCallMatch",
+		Metadata: make(map[string]any),
+	}
+
+	if in.ExpectedToolCalls == nil {
+		result.Error = api.ErrNoExpectedValue
+		result.Score = 0
+		return result
+	}
+
+	expected, output := in.ExpectedToolCalls, in.ToolCalls
+	result.Metadata["expected_count"] = len(expected)
+	result.Metadata["output_count"] = len(output)
+	result.Metadata["order_sensitive"] = s.opts.OrderSensitive
+
+	if len(expected) == 0 && len(output) == 0 {
+		result.Score = 1.0
+		result.Metadata["calls"] = []ToolCallResult{}
+		result.Metadata["missing_calls"] = []int{}
+		result.Metadata["extra_calls"] = []int{}
+		result.Metadata["wrong_tool_calls"] = []int{}
+		return result
+	}
+
+	pairs := make([][]ToolCallResult, len(expected))
+	weights := make([][]float64, len(expected))
+	for i, exp := range expected {
+		pairs[i] = make([]ToolCallResult, len(output))
+		weights[i] = make([]float64, len(output))
+		for j, out := range output {
+			pair, err := s.compare(ctx, exp, out)
+			if err != nil {
+				result.Error = err
+				result.Score = 0
+				return result
+			}
+			pair.ExpectedIndex, pair.OutputIndex = i, j
+			pairs[i][j] = pair
+			weights[i][j] = pair.Score + pairingBonus
+		}
+	}
+
+	var matches []int
+	if s.opts.OrderSensitive {
+		matches = orderedAlignment(weights, len(output))
+	} else {
+		matches = assignment.Optimal(weights)
+	}
+
+	calls := []ToolCallResult{}
+	missing, extra, wrongTool := []int{}, []int{}, []int{}
+	outputMatched := make([]bool, len(output))
+	total := 0.0
+	for i, j := range matches {
+		if j < 0 {
+			missing = append(missing, i)
+			continue
+		}
+		outputMatched[j] = true
+		pair := pairs[i][j]
+		if pair.WrongTool {
+			wrongTool = append(wrongTool, i)
+		}
+		calls = append(calls, pair)
+		total += pair.Score
+	}
+	for j, matched := range outputMatched {
+		if !matched {
+			extra = append(extra, j)
+		}
+	}
+
+	result.Score = total / float64(max(len(expected), len(output)))
+	result.Metadata["calls"] = calls
+	result.Metadata["missing_calls"] = missing
+	result.Metadata["extra_calls"] = extra
+	result.Metadata["wrong_tool_calls"] = wrongTool
+
+	return result
+}
+
+// compare scores a produced call against an expected call
+func (s *toolCallMatchScorer) compare(ctx context.Context, expected, output api.ToolCall) (ToolCallResult, error) {
+	pair := ToolCallResult{ExpectedName: expected.Name, OutputName: output.Name}
+	if expected.Name != output.Name {
+		pair.WrongTool = true
+		return pair, nil
+	}
+
+	pair.ArgumentScores = make(map[string]float64)
+	total, count := 0.0, 0
+	for _, name := range sortedArgumentNames(expected.Arguments) {
+		count++
+		actual, ok := output.Arguments[name]
+		if !ok {
+			pair.MissingArguments = append(pair.MissingArguments, name)
+			pair.ArgumentScores[name] = 0
+			continue
+		}
+		score, err := s.comparator(expected.Name, name)(ctx, expected.Arguments[name], actual)
+		if err != nil {
+			return pair, err
+		}
+		pair.ArgumentScores[name] = score
+		total += score
+		if score < 1 {
+			pair.MismatchedArguments = append(pair.MismatchedArguments, name)
+		}
+	}
+	for _, name := range sortedArgumentNames(output.Arguments) {
+		if _, ok := expected.Arguments[name]; ok {
+			continue
+		}
+		pair.ExtraArguments = append(pair.ExtraArguments, name)
+		if !s.opts.ArgumentSubset {
+			count++
+			pair.ArgumentScores[name] = 0
+		}
+	}
+
+	if count == 0 {
+		pair.Score = 1.0
+	} else {
+		pair.Score = total / float64(count)
+	}
+	return pair, nil
+}
+
+// comparator returns the comparator configured for an argument of a tool
+func (s *toolCallMatchScorer) comparator(tool, argument string) ArgumentComparator {
+	if c, ok := s.opts.Comparators[tool+"."+argument]; ok {
+		return c
+	}
+	if c, ok := s.opts.Comparators[argument]; ok {
+		return c
+	}
+	if s.opts.DefaultComparator != nil {
+		return s.opts.DefaultComparator
+	}
+	return ExactArgument()
+}
+
+// orderedAlignment returns the order-preserving matching of rows to columns maximizing the total weight
+// matches[i] is the column matched to row i, or -1 if the row is left unmatched
+func orderedAlignment(weights [][]float64, cols int) []int {
+	rows := len(weights)
+	dp := make([][]float64, rows+1)
+	for i := range dp {
+		dp[i] = make([]float64, cols+1)
+	}
+	for i := 1; i <= rows; i++ {
+		for j := 1; j <= cols; j++ {
+			dp[i][j] = max(dp[i-1][j], dp[i][j-1], dp[i-1][j-1]+weights[i-1][j-1])
+		}
+	}
+
+	matches := make([]int, rows)
+	for i := range matches {
+		matches[i] = -1
+	}
+	for i, j := rows, cols; i > 0 && j > 0; {
+		switch {
+		case dp[i][j] == dp[i-1][j-1]+weights[i-1][j-1]:
+			matches[i-1] = j - 1
+			i, j = i-1, j-1
+		case dp[i][j] == dp[i-1][j]:
+			i--
+		default:
+			j--
+		}
+	}
+	return matches
+}
+
+func sortedArgumentNames(args map[string]any) []string {
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
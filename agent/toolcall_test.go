@@ -0,0 +1,251 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// mockEmbedder maps texts to fixed vectors for unit tests
+type mockEmbedder struct {
+	vectors map[string][]float64
+}
+
+func (m *mockEmbedder) Embed(ctx context.Context, text string) ([]float64, error) {
+	if v, ok := m.vectors[text]; ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("no vector for %q", text)
+}
+
+func call(name string, args map[string]any) api.ToolCall {
+	return api.ToolCall{Name: name, Arguments: args}
+}
+
+func TestToolCallMatch_Unit(t *testing.T) {
+	ctx := context.Background()
+
+	embedder := &mockEmbedder{vectors: map[string][]float64{
+		"New York City": {1, 0},
+		"NYC":           {0.99, 0.14},
+		"Boston":        {0, 1},
+		"Manhattan":     {0.6, 0.8},
+	}}
+
+	tests := []struct {
+		name          string
+		opts          ToolCallMatchOptions
+		output        []api.ToolCall
+		expected      []api.ToolCall
+		wantErr       error
+		wantScore     float64
+		wantMissing   []int
+		wantExtra     []int
+		wantWrongTool []int
+	}{
+		{
+			name:      "identical calls",
+			output:    []api.ToolCall{call("get_weather", map[string]any{"city": "Paris", "days": 3.0})},
+			expected:  []api.ToolCall{call("get_weather", map[string]any{"city": "Paris", "days": 3})},
+			wantScore: 1.0,
+		},
+		{
+			name:      "one wrong argument gives partial credit",
+			output:    []api.ToolCall{call("get_weather", map[string]any{"city": "Paris", "days": 5})},
+			expected:  []api.ToolCall{call("get_weather", map[string]any{"city": "Paris", "days": 3})},
+			wantScore: 0.5,
+		},
+		{
+			name:      "missing argument",
+			output:    []api.ToolCall{call("get_weather", map[string]any{"city": "Paris"})},
+			expected:  []api.ToolCall{call("get_weather", map[string]any{"city": "Paris", "days": 3})},
+			wantScore: 0.5,
+		},
+		{
+			name:      "extra argument penalized by default",
+			output:    []api.ToolCall{call("get_weather", map[string]any{"city": "Paris", "units": "metric"})},
+			expected:  []api.ToolCall{call("get_weather", map[string]any{"city": "Paris"})},
+			wantScore: 0.5,
+		},
+		{
+			name:      "extra argument ignored with subset matching",
+			opts:      ToolCallMatchOptions{ArgumentSubset: true},
+			output:    []api.ToolCall{call("get_weather", map[string]any{"city": "Paris", "units": "metric"})},
+			expected:  []api.ToolCall{call("get_weather", map[string]any{"city": "Paris"})},
+			wantScore: 1.0,
+		},
+		{
+			name:          "wrong tool",
+			output:        []api.ToolCall{call("get_time", map[string]any{"city": "Paris"})},
+			expected:      []api.ToolCall{call("get_weather", map[string]any{"city": "Paris"})},
+			wantScore:     0.0,
+			wantWrongTool: []int{0},
+		},
+		{
+			name:      "any order by default",
+			output:    []api.ToolCall{call("b", nil), call("a", nil)},
+			expected:  []api.ToolCall{call("a", nil), call("b", nil)},
+			wantScore: 1.0,
+		},
+		{
+			name:        "order sensitive",
+			opts:        ToolCallMatchOptions{OrderSensitive: true},
+			output:      []api.ToolCall{call("b", nil), call("a", nil)},
+			expected:    []api.ToolCall{call("a", nil), call("b", nil)},
+			wantScore:   0.5,
+			wantMissing: []int{1},
+			wantExtra:   []int{0},
+		},
+		{
+			name:        "missing call",
+			output:      []api.ToolCall{call("a", nil)},
+			expected:    []api.ToolCall{call("a", nil), call("b", nil)},
+			wantScore:   0.5,
+			wantMissing: []int{1},
+		},
+		{
+			name:      "extra call",
+			output:    []api.ToolCall{call("a", nil), call("a", nil), call("b", nil)},
+			expected:  []api.ToolCall{call("a", nil), call("b", nil)},
+			wantScore: 2.0 / 3.0,
+			wantExtra: []int{1},
+		},
+		{
+			name:      "numeric tolerance comparator",
+			opts:      ToolCallMatchOptions{Comparators: map[string]ArgumentComparator{"amount": NumericArgument(0.01)}},
+			output:    []api.ToolCall{call("refund", map[string]any{"amount": "19.999"})},
+			expected:  []api.ToolCall{call("refund", map[string]any{"amount": 20})},
+			wantScore: 1.0,
+		},
+		{
+			name: "tool-specific comparator",
+			opts: ToolCallMatchOptions{Comparators: map[string]ArgumentComparator{
+				"search.query": EmbeddingArgument(embedder, 0.9),
+			}},
+			output:    []api.ToolCall{call("search", map[string]any{"query": "NYC"})},
+			expected:  []api.ToolCall{call("search", map[string]any{"query": "New York City"})},
+			wantScore: 1.0,
+		},
+		{
+			name: "embedding comparator rejects different meaning",
+			opts: ToolCallMatchOptions{Comparators: map[string]ArgumentComparator{
+				"query": EmbeddingArgument(embedder, 0.9),
+			}},
+			output:    []api.ToolCall{call("search", map[string]any{"query": "Boston"})},
+			expected:  []api.ToolCall{call("search", map[string]any{"query": "New York City"})},
+			wantScore: 0.0,
+		},
+		{
+			name: "embedding comparator gives cosine similarity as partial credit",
+			opts: ToolCallMatchOptions{Comparators: map[string]ArgumentComparator{
+				"query": EmbeddingArgument(embedder, 0.9),
+			}},
+			output:    []api.ToolCall{call("search", map[string]any{"query": "Manhattan"})},
+			expected:  []api.ToolCall{call("search", map[string]any{"query": "New York City"})},
+			wantScore: 0.6,
+		},
+		{
+			name:      "no calls expected and none made",
+			output:    nil,
+			expected:  []api.ToolCall{},
+			wantScore: 1.0,
+		},
+		{
+			name:      "no calls expected but one made",
+			output:    []api.ToolCall{call("a", nil)},
+			expected:  []api.ToolCall{},
+			wantScore: 0.0,
+			wantExtra: []int{0},
+		},
+		{
+			name:    "no expected calls given",
+			output:  []api.ToolCall{call("a", nil)},
+			wantErr: api.ErrNoExpectedValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ToolCallMatch(tt.opts).Score(ctx, api.ScoreInputs{ToolCalls: tt.output, ExpectedToolCalls: tt.expected})
+
+			if result.Name != "ToolCallMatch" {
+				t.Errorf("ToolCallMatch.Score() name = %v, want ToolCallMatch", result.Name)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(result.Error, tt.wantErr) {
+					t.Errorf("ToolCallMatch.Score() error = %v, want %v", result.Error, tt.wantErr)
+				}
+				return
+			}
+			if result.Error != nil {
+				t.Fatalf("ToolCallMatch.Score() unexpected error = %v", result.Error)
+			}
+			if math.Abs(result.Score-tt.wantScore) > 1e-9 {
+				t.Errorf("ToolCallMatch.Score() score = %v, want %v", result.Score, tt.wantScore)
+			}
+			checkIndices(t, "missing_calls", result.Metadata["missing_calls"], tt.wantMissing)
+			checkIndices(t, "extra_calls", result.Metadata["extra_calls"], tt.wantExtra)
+			checkIndices(t, "wrong_tool_calls", result.Metadata["wrong_tool_calls"], tt.wantWrongTool)
+		})
+	}
+}
+
+func TestToolCallMatch_ArgumentReport(t *testing.T) {
+	result := ToolCallMatch(ToolCallMatchOptions{}).Score(context.Background(), api.ScoreInputs{
+		ToolCalls:         []api.ToolCall{call("book", map[string]any{"date": "2024-05-01", "seats": 3, "note": "window"})},
+		ExpectedToolCalls: []api.ToolCall{call("book", map[string]any{"date": "2024-05-01", "seats": 2, "city": "Oslo"})},
+	})
+	if result.Error != nil {
+		t.Fatalf("ToolCallMatch.Score() unexpected error = %v", result.Error)
+	}
+
+	calls := result.Metadata["calls"].([]ToolCallResult)
+	if len(calls) != 1 {
+		t.Fatalf("calls = %v, want 1 entry", calls)
+	}
+	got := calls[0]
+	if !reflect.DeepEqual(got.MissingArguments, []string{"city"}) {
+		t.Errorf("MissingArguments = %v, want [city]", got.MissingArguments)
+	}
+	if !reflect.DeepEqual(got.ExtraArguments, []string{"note"}) {
+		t.Errorf("ExtraArguments = %v, want [note]", got.ExtraArguments)
+	}
+	if !reflect.DeepEqual(got.MismatchedArguments, []string{"seats"}) {
+		t.Errorf("MismatchedArguments = %v, want [seats]", got.MismatchedArguments)
+	}
+	// date matches; city, seats and note do not
+	if math.Abs(result.Score-0.25) > 1e-9 {
+		t.Errorf("ToolCallMatch.Score() score = %v, want 0.25", result.Score)
+	}
+}
+
+func TestToolCallMatch_ComparatorError(t *testing.T) {
+	failing := func(ctx context.Context, expected, actual any) (float64, error) {
+		return 0, fmt.Errorf("comparator failed")
+	}
+	result := ToolCallMatch(ToolCallMatchOptions{DefaultComparator: failing}).Score(context.Background(), api.ScoreInputs{
+		ToolCalls:         []api.ToolCall{call("a", map[string]any{"x": 1})},
+		ExpectedToolCalls: []api.ToolCall{call("a", map[string]any{"x": 1})},
+	})
+	if result.Error == nil {
+		t.Error("ToolCallMatch.Score() expected comparator error")
+	}
+	if result.Score != 0 {
+		t.Errorf("ToolCallMatch.Score() score = %v, want 0", result.Score)
+	}
+}
+
+func checkIndices(t *testing.T, key string, got any, want []int) {
+	t.Helper()
+	if want == nil {
+		want = []int{}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s = %v, want %v", key, got, want)
+	}
+}
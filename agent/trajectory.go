@@ -0,0 +1,250 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// TrajectoryMatchMode selects how the produced trajectory is compared to the reference
+type TrajectoryMatchMode int
+
+const (
+	// TrajectoryExact requires the same tool calls in the same order with nothing extra (default)
+	TrajectoryExact TrajectoryMatchMode = iota
+	// TrajectoryInOrder requires the reference tool calls in order; extra calls in between are allowed
+	TrajectoryInOrder
+	// TrajectoryAnyOrder requires the reference tool calls in any order; extra calls are allowed
+	TrajectoryAnyOrder
+)
+
+func (m TrajectoryMatchMode) String() string {
+	switch m {
+	case TrajectoryInOrder:
+		return "in_order"
+	case TrajectoryAnyOrder:
+		return "any_order"
+	default:
+		return "exact"
+	}
+}
+
+// TrajectoryMatchOptions configures the TrajectoryMatch scorer
+type TrajectoryMatchOptions struct {
+	// Mode selects exact, in-order or any-order matching (default: TrajectoryExact)
+	Mode TrajectoryMatchMode
+	// MatchArguments also requires JSON-equal arguments; by default only tool names are compared
+	MatchArguments bool
+}
+
+// TrajectoryMatch returns a scorer that compares the tool calls in Trajectory against ExpectedTrajectory
+// Thoughts and observations are ignored. TrajectoryExact scores 1.0 or 0.0; the other modes give
+// partial credit equal to the fraction of reference calls matched
+func TrajectoryMatch(opts TrajectoryMatchOptions) api.Scorer {
+	return &trajectoryMatchScorer{opts: opts}
+}
+
+type trajectoryMatchScorer struct {
+	opts TrajectoryMatchOptions
+}
+
+func (s *trajectoryMatchScorer) Score(ctx context.Context, in api.ScoreInputs) api.Score {
+	result := api.Score{
+		Name:     "TrajectoryMatch",
+		Metadata: make(map[string]any),
+	}
+
+	if len(in.ExpectedTrajectory) == 0 {
+		result.Error = api.ErrNoExpectedValue
+		result.Score = 0
+		return result
+	}
+
+	expected := s.keys(api.TrajectoryToolCalls(in.ExpectedTrajectory))
+	output := s.keys(api.TrajectoryToolCalls(in.Trajectory))
+
+	var expectedMatched, outputMatched []bool
+	if s.opts.Mode == TrajectoryAnyOrder {
+		expectedMatched, outputMatched = unorderedMatch(expected, output)
+	} else {
+		expectedMatched, outputMatched = subsequenceMatch(expected, output)
+	}
+
+	matched := 0
+	missing := []string{}
+	for i, ok := range expectedMatched {
+		if ok {
+			matched++
+		} else {
+			missing = append(missing, expected[i].name)
+		}
+	}
+	extra := []string{}
+	for j, ok := range outputMatched {
+		if !ok {
+			extra = append(extra, output[j].name)
+		}
+	}
+
+	isMatch := matched == len(expected)
+	if s.opts.Mode == TrajectoryExact {
+		isMatch = isMatch && len(extra) == 0
+	}
+
+	switch {
+	case isMatch:
+		result.Score = 1.0
+	case s.opts.Mode == TrajectoryExact || len(expected) == 0:
+		result.Score = 0.0
+	default:
+		result.Score = float64(matched) / float64(len(expected))
+	}
+
+	result.Metadata["mode"] = s.opts.Mode.String()
+	result.Metadata["match"] = isMatch
+	result.Metadata["matched_steps"] = matched
+	result.Metadata["expected_steps"] = len(expected)
+	result.Metadata["output_steps"] = len(output)
+	result.Metadata["missing_steps"] = missing
+	result.Metadata["extra_steps"] = extra
+
+	return result
+}
+
+// stepKey identifies a tool call for trajectory comparison
+type stepKey struct {
+	name string
+	args string
+}
+
+func (s *trajectoryMatchScorer) keys(calls []api.ToolCall) []stepKey {
+	keys := make([]stepKey, len(calls))
+	for i, call := range calls {
+		keys[i] = stepKey{name: call.Name}
+		if s.opts.MatchArguments {
+			keys[i].args = canonicalArguments(call.Arguments)
+		}
+	}
+	return keys
+}
+
+// subsequenceMatch marks the longest common subsequence of expected and output
+func subsequenceMatch(expected, output []stepKey) (expectedMatched, outputMatched []bool) {
+	n, m := len(expected), len(output)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if expected[i] == output[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	expectedMatched, outputMatched = make([]bool, n), make([]bool, m)
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case expected[i] == output[j]:
+			expectedMatched[i], outputMatched[j] = true, true
+			i, j = i+1, j+1
+		case lcs[i+1][j] > lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return expectedMatched, outputMatched
+}
+
+// unorderedMatch pairs equal steps regardless of position, each step being used at most once
+func unorderedMatch(expected, output []stepKey) (expectedMatched, outputMatched []bool) {
+	expectedMatched, outputMatched = make([]bool, len(expected)), make([]bool, len(output))
+	for i, exp := range expected {
+		for j, out := range output {
+			if !outputMatched[j] && exp == out {
+				expectedMatched[i], outputMatched[j] = true, true
+				break
+			}
+		}
+	}
+	return expectedMatched, outputMatched
+}
+
+// RedundantStepsOptions configures the RedundantSteps scorer
+type RedundantStepsOptions struct {
+	// IgnoreArguments treats any repeated call of the same tool as redundant, regardless of arguments
+	IgnoreArguments bool
+}
+
+// RedundantSteps returns a scorer that penalizes repeated tool calls in Trajectory
+// A call is redundant when the same tool was already called with the same arguments
+// Returns 1.0 minus the fraction of redundant tool calls
+func RedundantSteps(opts RedundantStepsOptions) api.Scorer {
+	return &redundantStepsScorer{opts: opts}
+}
+
+type redundantStepsScorer struct {
+	opts RedundantStepsOptions
+}
+
+func (s *redundantStepsScorer) Score(ctx context.Context, in api.ScoreInputs) api.Score {
+	result := api.Score{
+		Name:     "RedundantSteps",
+		Metadata: make(map[string]any),
+	}
+
+	if len(in.Trajectory) == 0 {
+		result.Error = api.ErrNoTrajectory
+		result.Score = 0
+		return result
+	}
+
+	seen := make(map[stepKey]bool)
+	redundantSteps := []int{}
+	redundantCalls := []string{}
+	toolCalls := 0
+	for i, step := range in.Trajectory {
+		if step.Type != api.StepToolCall || step.ToolCall == nil {
+			continue
+		}
+		toolCalls++
+		key := stepKey{name: step.ToolCall.Name}
+		if !s.opts.IgnoreArguments {
+			key.args = canonicalArguments(step.ToolCall.Arguments)
+		}
+		if seen[key] {
+			redundantSteps = append(redundantSteps, i)
+			redundantCalls = append(redundantCalls, step.ToolCall.Name)
+			continue
+		}
+		seen[key] = true
+	}
+
+	if toolCalls == 0 {
+		result.Score = 1.0
+	} else {
+		result.Score = 1.0 - float64(len(redundantSteps))/float64(toolCalls)
+	}
+	result.Metadata["tool_calls"] = toolCalls
+	result.Metadata["redundant_steps"] = redundantSteps
+	result.Metadata["redundant_calls"] = redundantCalls
+
+	return result
+}
+
+// canonicalArguments renders arguments as JSON with sorted keys, so equal arguments compare equal
+func canonicalArguments(args map[string]any) string {
+	if len(args) == 0 {
+		return "{}"
+	}
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return ""
+	}
+	return string(raw)
+}
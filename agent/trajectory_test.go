@@ -0,0 +1,237 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+func toolStep(name string, args map[string]any) api.Step {
+	return api.Step{Type: api.StepToolCall, ToolCall: &api.ToolCall{Name: name, Arguments: args}}
+}
+
+func TestTrajectoryMatch_Unit(t *testing.T) {
+	ctx := context.Background()
+
+	reference := []api.Step{
+		{Type: api.StepThought, Content: "find the order first"},
+		toolStep("lookup_order", map[string]any{"id": "A1"}),
+		{Type: api.StepObservation, Content: `{"status": "shipped"}`},
+		toolStep("track_package", map[string]any{"carrier": "ups"}),
+	}
+
+	tests := []struct {
+		name        string
+		opts        TrajectoryMatchOptions
+		trajectory  []api.Step
+		expected    []api.Step
+		wantErr     error
+		wantScore   float64
+		wantMatch   bool
+		wantMissing []string
+		wantExtra   []string
+	}{
+		{
+			name: "exact match ignores thoughts and observations",
+			trajectory: []api.Step{
+				toolStep("lookup_order", map[string]any{"id": "B2"}),
+				toolStep("track_package", nil),
+			},
+			expected:  reference,
+			wantScore: 1.0,
+			wantMatch: true,
+		},
+		{
+			name: "exact fails on extra step",
+			trajectory: []api.Step{
+				toolStep("lookup_order", nil),
+				toolStep("search_faq", nil),
+				toolStep("track_package", nil),
+			},
+			expected:  reference,
+			wantScore: 0.0,
+			wantExtra: []string{"search_faq"},
+		},
+		{
+			name: "in order allows extra steps",
+			opts: TrajectoryMatchOptions{Mode: TrajectoryInOrder},
+			trajectory: []api.Step{
+				toolStep("lookup_order", nil),
+				toolStep("search_faq", nil),
+				toolStep("track_package", nil),
+			},
+			expected:  reference,
+			wantScore: 1.0,
+			wantMatch: true,
+			wantExtra: []string{"search_faq"},
+		},
+		{
+			name: "in order gives partial credit for swapped steps",
+			opts: TrajectoryMatchOptions{Mode: TrajectoryInOrder},
+			trajectory: []api.Step{
+				toolStep("track_package", nil),
+				toolStep("lookup_order", nil),
+			},
+			expected:    reference,
+			wantScore:   0.5,
+			wantMissing: []string{"track_package"},
+			wantExtra:   []string{"track_package"},
+		},
+		{
+			name: "any order accepts swapped steps",
+			opts: TrajectoryMatchOptions{Mode: TrajectoryAnyOrder},
+			trajectory: []api.Step{
+				toolStep("track_package", nil),
+				toolStep("lookup_order", nil),
+			},
+			expected:  reference,
+			wantScore: 1.0,
+			wantMatch: true,
+		},
+		{
+			name: "any order missing step",
+			opts: TrajectoryMatchOptions{Mode: TrajectoryAnyOrder},
+			trajectory: []api.Step{
+				toolStep("lookup_order", nil),
+			},
+			expected:    reference,
+			wantScore:   0.5,
+			wantMissing: []string{"track_package"},
+		},
+		{
+			name: "arguments compared when requested",
+			opts: TrajectoryMatchOptions{MatchArguments: true},
+			trajectory: []api.Step{
+				toolStep("lookup_order", map[string]any{"id": "B2"}),
+				toolStep("track_package", map[string]any{"carrier": "ups"}),
+			},
+			expected:    reference,
+			wantScore:   0.0,
+			wantMissing: []string{"lookup_order"},
+			wantExtra:   []string{"lookup_order"},
+		},
+		{
+			name:       "no reference trajectory",
+			trajectory: reference,
+			wantErr:    api.ErrNoExpectedValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := TrajectoryMatch(tt.opts).Score(ctx, api.ScoreInputs{Trajectory: tt.trajectory, ExpectedTrajectory: tt.expected})
+
+			if result.Name != "TrajectoryMatch" {
+				t.Errorf("TrajectoryMatch.Score() name = %v, want TrajectoryMatch", result.Name)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(result.Error, tt.wantErr) {
+					t.Errorf("TrajectoryMatch.Score() error = %v, want %v", result.Error, tt.wantErr)
+				}
+				return
+			}
+			if result.Error != nil {
+				t.Fatalf("TrajectoryMatch.Score() unexpected error = %v", result.Error)
+			}
+			if math.Abs(result.Score-tt.wantScore) > 1e-9 {
+				t.Errorf("TrajectoryMatch.Score() score = %v, want %v", result.Score, tt.wantScore)
+			}
+			if result.Metadata["match"] != tt.wantMatch {
+				t.Errorf("TrajectoryMatch.Score() match = %v, want %v", result.Metadata["match"], tt.wantMatch)
+			}
+			checkNames(t, "missing_steps", result.Metadata["missing_steps"], tt.wantMissing)
+			checkNames(t, "extra_steps", result.Metadata["extra_steps"], tt.wantExtra)
+		})
+	}
+}
+
+func TestRedundantSteps_Unit(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		opts          RedundantStepsOptions
+		trajectory    []api.Step
+		wantErr       error
+		wantScore     float64
+		wantRedundant []int
+	}{
+		{
+			name: "no repeats",
+			trajectory: []api.Step{
+				toolStep("search", map[string]any{"q": "a"}),
+				{Type: api.StepObservation, Content: "results"},
+				toolStep("search", map[string]any{"q": "b"}),
+			},
+			wantScore:     1.0,
+			wantRedundant: []int{},
+		},
+		{
+			name: "identical call repeated",
+			trajectory: []api.Step{
+				toolStep("search", map[string]any{"q": "a", "limit": 5}),
+				toolStep("search", map[string]any{"limit": 5, "q": "a"}),
+				toolStep("open", map[string]any{"url": "x"}),
+				toolStep("search", map[string]any{"q": "a", "limit": 5}),
+			},
+			wantScore:     0.5,
+			wantRedundant: []int{1, 3},
+		},
+		{
+			name: "ignore arguments",
+			opts: RedundantStepsOptions{IgnoreArguments: true},
+			trajectory: []api.Step{
+				toolStep("search", map[string]any{"q": "a"}),
+				toolStep("search", map[string]any{"q": "b"}),
+			},
+			wantScore:     0.5,
+			wantRedundant: []int{1},
+		},
+		{
+			name:          "thoughts only",
+			trajectory:    []api.Step{{Type: api.StepThought, Content: "I know the answer"}},
+			wantScore:     1.0,
+			wantRedundant: []int{},
+		},
+		{
+			name:    "no trajectory",
+			wantErr: api.ErrNoTrajectory,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RedundantSteps(tt.opts).Score(ctx, api.ScoreInputs{Trajectory: tt.trajectory})
+
+			if result.Name != "RedundantSteps" {
+				t.Errorf("RedundantSteps.Score() name = %v, want RedundantSteps", result.Name)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(result.Error, tt.wantErr) {
+					t.Errorf("RedundantSteps.Score() error = %v, want %v", result.Error, tt.wantErr)
+				}
+				return
+			}
+			if math.Abs(result.Score-tt.wantScore) > 1e-9 {
+				t.Errorf("RedundantSteps.Score() score = %v, want %v", result.Score, tt.wantScore)
+			}
+			if got := result.Metadata["redundant_steps"]; !reflect.DeepEqual(got, tt.wantRedundant) {
+				t.Errorf("RedundantSteps.Score() redundant_steps = %v, want %v", got, tt.wantRedundant)
+			}
+		})
+	}
+}
+
+func checkNames(t *testing.T, key string, got any, want []string) {
+	t.Helper()
+	if want == nil {
+		want = []string{}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s = %v, want %v", key, got, want)
+	}
+}
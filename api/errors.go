@@ -7,4 +7,12 @@ var (
 	ErrNoExpectedValue = errors.New("expected value is required for this scorer")
 	// ErrLLMGenerationFailed is returned when LLM generation fails
 	ErrLLMGenerationFailed = errors.New("LLM generation failed")
+	// ErrNoConversation is returned when a conversation-level scorer receives no messages
+	ErrNoConversation = errors.New("conversation messages are required for this scorer")
+	// ErrNoTrajectory is returned when a trajectory scorer receives no trajectory
+	ErrNoTrajectory = errors.New("trajectory is required for this scorer")
+	// ErrMultimodalNotSupported is returned when attachments are given but the LLM generator only accepts text
+	ErrMultimodalNotSupported = errors.New("LLM generator does not support multimodal content")
+	// ErrBudgetExceeded is returned when a budget guard refuses a provider call
+	ErrBudgetExceeded = errors.New("budget exceeded")
 )
@@ -12,6 +12,87 @@ type LLMGenerator interface {
 	StructuredGenerate(ctx context.Context, prompt string, schema map[string]interface{}) (map[string]interface{}, error)
 }
 
+// Usage reports the tokens consumed by one or more provider calls
+type Usage struct {
+	// Model is the model that served the calls
+	Model string `json:"model,omitempty"`
+	// Calls is the number of provider calls
+	Calls int `json:"calls"`
+	// PromptTokens is the number of input tokens
+	PromptTokens int `json:"prompt_tokens"`
+	// OutputTokens is the number of generated response tokens, excluding thinking
+	OutputTokens int `json:"output_tokens"`
+	// ThinkingTokens is the number of tokens spent on model reasoning
+	ThinkingTokens int `json:"thinking_tokens"`
+}
+
+// TotalTokens returns the sum of prompt, output and thinking tokens
+func (u Usage) TotalTokens() int {
+	return u.PromptTokens + u.OutputTokens + u.ThinkingTokens
+}
+
+// Add accumulates other into u, keeping the first non-empty model name
+func (u *Usage) Add(other Usage) {
+	if u.Model == "" {
+		u.Model = other.Model
+	}
+	u.Calls += other.Calls
+	u.PromptTokens += other.PromptTokens
+	u.OutputTokens += other.OutputTokens
+	u.ThinkingTokens += other.ThinkingTokens
+}
+
+// GenerateResult is a structured generation result together with the tokens it consumed
+type GenerateResult struct {
+	// Data is the generated structured data
+	Data map[string]interface{}
+	// Usage is the token usage of the call
+	Usage Usage
+}
+
+// UsageReportingGenerator is an LLMGenerator that reports token usage for each call
+// LLM judges use it when available and record the usage in score metadata under "usage"
+type UsageReportingGenerator interface {
+	LLMGenerator
+	// StructuredGenerateContentWithUsage generates structured data from content parts and reports token usage
+	StructuredGenerateContentWithUsage(ctx context.Context, parts []Part, schema map[string]interface{}) (GenerateResult, error)
+}
+
+// GenerationConfigReporter is implemented by generators that can report their effective generation settings
+// LLM judges record the reported settings in score metadata under "generation_config"
+type GenerationConfigReporter interface {
+	// GenerationConfig returns the model and generation settings used for requests
+	GenerationConfig() map[string]any
+}
+
+// Part is a piece of multimodal content: either text or inline binary data such as an image
+type Part struct {
+	// Text is the text content of a text part
+	Text string
+	// Data holds inline bytes, e.g. a PNG image
+	Data []byte
+	// MIMEType describes Data, e.g. "image/png" (required when Data is set)
+	MIMEType string
+}
+
+// TextPart returns a text content part
+func TextPart(text string) Part {
+	return Part{Text: text}
+}
+
+// ImagePart returns an inline image content part
+func ImagePart(data []byte, mimeType string) Part {
+	return Part{Data: data, MIMEType: mimeType}
+}
+
+// MultimodalGenerator is an LLMGenerator that also accepts multimodal content parts
+// A Gemini implementation is provided in the gemini subpackage
+type MultimodalGenerator interface {
+	LLMGenerator
+	// StructuredGenerateContent is like StructuredGenerate, with the prompt given as ordered content parts
+	StructuredGenerateContent(ctx context.Context, parts []Part, schema map[string]interface{}) (map[string]interface{}, error)
+}
+
 // Embedder generates vector embeddings for text
 type Embedder interface {
 	// Embed generates an embedding vector for the given text
@@ -19,6 +100,22 @@ type Embedder interface {
 	Embed(ctx context.Context, text string) ([]float64, error)
 }
 
+// BatchEmbedder is an Embedder that can embed many texts in one call
+// Embedding scorers use EmbedBatch when the embedder implements it
+type BatchEmbedder interface {
+	Embedder
+	// EmbedBatch generates embedding vectors for texts, returned in the same order
+	EmbedBatch(ctx context.Context, texts []string) ([][]float64, error)
+}
+
+// BatchRequestCounter is a BatchEmbedder that reports how many provider requests EmbedBatch sends
+// Budget guards use it to count a batch that is split into several requests
+type BatchRequestCounter interface {
+	BatchEmbedder
+	// BatchRequests returns the number of requests EmbedBatch sends for texts
+	BatchRequests(texts []string) int
+}
+
 // ModerationCategories contains all supported moderation category names
 // These are developer-friendly names that map to Google Cloud Natural Language API categories
 var ModerationCategories []string = []string{
@@ -72,16 +169,156 @@ type Score struct {
 	Error error
 }
 
+// Message roles used in conversation transcripts
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
+// ToolCall is a function/tool invocation emitted by a model
+type ToolCall struct {
+	// ID optionally correlates the call with its result
+	ID string `json:"id,omitempty"`
+	// Name is the tool or function name
+	Name string `json:"name"`
+	// Arguments are the decoded JSON arguments of the call
+	Arguments map[string]any `json:"arguments,omitempty"`
+}
+
+// Message is a single turn in a conversation
+type Message struct {
+	// Role is the speaker: RoleSystem, RoleUser, RoleAssistant or RoleTool
+	Role string `json:"role"`
+	// Content is the text of the message
+	Content string `json:"content"`
+	// ToolCalls are the tool invocations requested in this message (optional)
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+}
+
+// StepType classifies a step of an agent trajectory
+type StepType string
+
+// Agent trajectory step types
+const (
+	StepThought     StepType = "thought"
+	StepToolCall    StepType = "tool_call"
+	StepObservation StepType = "observation"
+)
+
+// Step is a single step of an agent trajectory: a thought, a tool call or an observation
+type Step struct {
+	// Type is the kind of step
+	Type StepType `json:"type"`
+	// Content is the thought or observation text
+	Content string `json:"content,omitempty"`
+	// ToolCall is the call made in a StepToolCall step
+	ToolCall *ToolCall `json:"tool_call,omitempty"`
+}
+
 // ScoreInputs carries inputs for scoring across different scorers.
 //
 // Fields usage conventions:
-// - Output:   the actual output produced by the model (required for most scorers)
-// - Expected: the reference/expected output (optional depending on scorer)
-// - Input:    the original prompt/context/question given to the model (optional)
+//   - Output:               the actual output produced by the model (required for most scorers)
+//   - Expected:             the reference/expected output (optional depending on scorer)
+//   - ExpectedAlternatives: additional acceptable references besides Expected (optional)
+//   - Input:                the original prompt/context/question given to the model (optional)
+//   - Messages:             the conversation history leading up to Output (optional)
+//   - ToolCalls:            the tool calls produced by the model (optional)
+//   - ExpectedToolCalls:    the reference tool calls; a non-nil empty slice expects no calls (optional)
+//   - Attachments:          images or other media given to the model alongside Input (optional)
+//   - Trajectory:           the steps the agent took before producing Output (optional)
+//   - ExpectedTrajectory:   the reference trajectory (optional)
+//
+// Conversation-level scorers treat a non-empty Output as the final assistant turn after Messages.
 type ScoreInputs struct {
-	Output   string
-	Expected string
-	Input    string
+	Output               string
+	Expected             string
+	ExpectedAlternatives []string
+	Input                string
+	Attachments          []Part
+	Messages             []Message
+	ToolCalls            []ToolCall
+	ExpectedToolCalls    []ToolCall
+	Trajectory           []Step
+	ExpectedTrajectory   []Step
+}
+
+// Transcript returns Messages followed by Output as a final assistant message, if Output is set
+func (in ScoreInputs) Transcript() []Message {
+	transcript := make([]Message, 0, len(in.Messages)+1)
+	transcript = append(transcript, in.Messages...)
+	if in.Output != "" {
+		transcript = append(transcript, Message{Role: RoleAssistant, Content: in.Output})
+	}
+	return transcript
+}
+
+// TrajectoryToolCalls returns the tool calls of a trajectory in order
+func TrajectoryToolCalls(steps []Step) []ToolCall {
+	var calls []ToolCall
+	for _, step := range steps {
+		if step.Type == StepToolCall && step.ToolCall != nil {
+			calls = append(calls, *step.ToolCall)
+		}
+	}
+	return calls
+}
+
+// References returns Expected followed by ExpectedAlternatives, skipping empty values
+func (in ScoreInputs) References() []string {
+	refs := make([]string, 0, 1+len(in.ExpectedAlternatives))
+	if in.Expected != "" {
+		refs = append(refs, in.Expected)
+	}
+	for _, alt := range in.ExpectedAlternatives {
+		if alt != "" {
+			refs = append(refs, alt)
+		}
+	}
+	return refs
+}
+
+// ReferenceAggregation selects how per-reference scores are combined when several references are given
+type ReferenceAggregation int
+
+const (
+	// AggregateBest takes the highest score across references (default)
+	AggregateBest ReferenceAggregation = iota
+	// AggregateMean averages scores across references
+	AggregateMean
+	// AggregateWorst takes the lowest score across references
+	AggregateWorst
+)
+
+// Aggregate combines per-reference scores into a single score
+// index identifies the reference whose details are reported in metadata:
+// the highest-scoring one for AggregateBest and AggregateMean, the lowest-scoring one for AggregateWorst
+func (a ReferenceAggregation) Aggregate(scores []float64) (score float64, index int) {
+	if len(scores) == 0 {
+		return 0, -1
+	}
+
+	best, worst, sum := 0, 0, 0.0
+	for i, s := range scores {
+		if s > scores[best] {
+			best = i
+		}
+		if s < scores[worst] {
+			worst = i
+		}
+		sum += s
+	}
+
+	switch a {
+	case AggregateMean:
+		return sum / float64(len(scores)), best
+	case AggregateWorst:
+		return scores[worst], worst
+	default:
+		return scores[best], best
+	}
 }
 
 // Scorer evaluates the quality of an output
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/datar-psa/goeval/experiment"
+)
+
+// scorerLimits collects repeated -scorer-max-regression scorer=value flags
+type scorerLimits map[string]float64
+
+func (l scorerLimits) String() string {
+	parts := make([]string, 0, len(l))
+	for name, limit := range l {
+		parts = append(parts, fmt.Sprintf("%s=%v", name, limit))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (l scorerLimits) Set(value string) error {
+	name, limit, ok := strings.Cut(value, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("expected scorer=value, got %q", value)
+	}
+	parsed, err := strconv.ParseFloat(limit, 64)
+	if err != nil {
+		return fmt.Errorf("invalid regression limit for %s: %w", name, err)
+	}
+	l[name] = parsed
+	return nil
+}
+
+func compareCommand(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("compare", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	limits := scorerLimits{}
+	var opts experiment.CompareOptions
+	flags.Float64Var(&opts.MaxRegression, "max-regression", 0, "largest allowed drop of a scorer's mean score")
+	flags.Var(limits, "scorer-max-regression", "allowed drop for one scorer as scorer=value (repeatable)")
+	flags.BoolVar(&opts.AllowMissing, "allow-missing", false, "pass even when rows or scorers are present in only one run")
+	flags.BoolVar(&opts.RequireSignificance, "require-significance", false, "only fail on drops that are statistically significant")
+	flags.Float64Var(&opts.Alpha, "alpha", experiment.DefaultAlpha, "significance level of the paired tests")
+	flags.Float64Var(&opts.MinRowDelta, "min-row-delta", 0, "smallest row score change counted as a regression or improvement")
+	flags.IntVar(&opts.TopN, "top", experiment.DefaultTopN, "number of regressed and improved rows listed per scorer")
+	asJSON := flags.Bool("json", false, "print the comparison as JSON")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 2 {
+		fmt.Fprintln(stderr, "goeval: compare takes a baseline and a candidate results file")
+		flags.Usage()
+		return exitUsage
+	}
+	opts.ScorerMaxRegression = limits
+
+	baseline, err := experiment.ReadResults(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "goeval: %v\n", err)
+		return exitUsage
+	}
+	candidate, err := experiment.ReadResults(flags.Arg(1))
+	if err != nil {
+		fmt.Fprintf(stderr, "goeval: %v\n", err)
+		return exitUsage
+	}
+
+	comparison, err := experiment.Compare(baseline, candidate, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "goeval: %v\n", err)
+		return exitUsage
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(comparison)
+	} else {
+		err = comparison.WriteTable(stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "goeval: %v\n", err)
+		return exitRunError
+	}
+	if !comparison.Passed {
+		return exitFailed
+	}
+	return exitOK
+}
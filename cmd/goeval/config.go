@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the eval config file; JSON configs are read as YAML
+type Config struct {
+	// Providers configures the model clients used by scorers
+	Providers ProvidersConfig `yaml:"providers"`
+	// Dataset is the path of the dataset file, relative to the config file
+	Dataset string `yaml:"dataset"`
+	// Output is the path of the JSONL results file, relative to the working directory (optional)
+	Output string `yaml:"output"`
+	// Concurrency is the number of rows scored in parallel (default: 4)
+	Concurrency int `yaml:"concurrency"`
+	// Stratify is a row tag, e.g. "category"; the summary is then also broken down by its values (optional)
+	Stratify string `yaml:"stratify"`
+	// Scorers are the scorers applied to every row
+	Scorers []ScorerConfig `yaml:"scorers"`
+}
+
+// ProvidersConfig configures the model clients used by scorers
+type ProvidersConfig struct {
+	// Gemini configures Gemini judges and embeddings (optional)
+	Gemini *GeminiConfig `yaml:"gemini"`
+	// Embedder selects the embedder: "gemini" (default when gemini.embedding_model is set) or "local"
+	Embedder string `yaml:"embedder"`
+	// Moderation enables the Google Cloud Natural Language moderation provider
+	Moderation bool `yaml:"moderation"`
+}
+
+// GeminiConfig configures the Gemini client
+// Without a project, the client is configured from the environment (e.g. GOOGLE_API_KEY)
+type GeminiConfig struct {
+	Project        string `yaml:"project"`
+	Location       string `yaml:"location"`
+	Model          string `yaml:"model"`
+	EmbeddingModel string `yaml:"embedding_model"`
+}
+
+// ScorerConfig declares one scorer
+type ScorerConfig struct {
+	// Name is the scorer type, e.g. "Factuality" or "ExactMatch"
+	Name string `yaml:"name"`
+	// Label distinguishes several scorers of the same type in results (default: Name)
+	Label string `yaml:"label"`
+	// Threshold is the minimum mean score across rows; below it the run fails (optional)
+	Threshold *float64 `yaml:"threshold"`
+	// Options are the scorer options, e.g. {threshold: 0.5} for Moderation; see registry.Decode
+	Options map[string]any `yaml:"options"`
+}
+
+// label returns the name the scorer is reported under
+func (c ScorerConfig) label() string {
+	if c.Label != "" {
+		return c.Label
+	}
+	return c.Name
+}
+
+// loadConfig reads and validates the config at path
+func loadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var config Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	if config.Dataset == "" {
+		return nil, fmt.Errorf("invalid config %s: dataset is required", path)
+	}
+	if !filepath.IsAbs(config.Dataset) {
+		config.Dataset = filepath.Join(filepath.Dir(path), config.Dataset)
+	}
+	if len(config.Scorers) == 0 {
+		return nil, fmt.Errorf("invalid config %s: at least one scorer is required", path)
+	}
+	labels := make(map[string]bool, len(config.Scorers))
+	for i, scorer := range config.Scorers {
+		if scorer.Name == "" {
+			return nil, fmt.Errorf("invalid config %s: scorers[%d]: name is required", path, i)
+		}
+		if labels[scorer.label()] {
+			return nil, fmt.Errorf("invalid config %s: scorers[%d]: duplicate scorer %q; set a label", path, i, scorer.label())
+		}
+		labels[scorer.label()] = true
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 4
+	}
+	return &config, nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// Row is one dataset row
+type Row struct {
+	// ID identifies the row in results (default: "row-<n>", numbered from 1)
+	ID                   string            `json:"id"`
+	Input                string            `json:"input"`
+	Output               string            `json:"output"`
+	Expected             string            `json:"expected"`
+	ExpectedAlternatives []string          `json:"expected_alternatives"`
+	Messages             []api.Message     `json:"messages"`
+	ToolCalls            []api.ToolCall    `json:"tool_calls"`
+	ExpectedToolCalls    []api.ToolCall    `json:"expected_tool_calls"`
+	Trajectory           []api.Step        `json:"trajectory"`
+	ExpectedTrajectory   []api.Step        `json:"expected_trajectory"`
+	Tags                 map[string]string `json:"tags"`
+}
+
+// Inputs returns the scorer inputs of the row
+func (r Row) Inputs() api.ScoreInputs {
+	return api.ScoreInputs{
+		Output:               r.Output,
+		Expected:             r.Expected,
+		ExpectedAlternatives: r.ExpectedAlternatives,
+		Input:                r.Input,
+		Messages:             r.Messages,
+		ToolCalls:            r.ToolCalls,
+		ExpectedToolCalls:    r.ExpectedToolCalls,
+		Trajectory:           r.Trajectory,
+		ExpectedTrajectory:   r.ExpectedTrajectory,
+	}
+}
+
+// loadDataset reads rows from a JSON array or a JSONL file
+func loadDataset(path string) ([]Row, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+
+	var rows []Row
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &rows); err != nil {
+			return nil, fmt.Errorf("invalid dataset %s: %w", path, err)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+		for line := 1; scanner.Scan(); line++ {
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" {
+				continue
+			}
+			var row Row
+			if err := json.Unmarshal([]byte(text), &row); err != nil {
+				return nil, fmt.Errorf("invalid dataset %s: line %d: %w", path, line, err)
+			}
+			rows = append(rows, row)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read dataset: %w", err)
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("dataset %s has no rows", path)
+	}
+	ids := make(map[string]bool, len(rows))
+	for i := range rows {
+		if rows[i].ID == "" {
+			rows[i].ID = fmt.Sprintf("row-%d", i+1)
+		}
+		if ids[rows[i].ID] {
+			return nil, fmt.Errorf("invalid dataset %s: duplicate row id %q", path, rows[i].ID)
+		}
+		ids[rows[i].ID] = true
+	}
+	return rows, nil
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/datar-psa/goeval/api"
+	"github.com/datar-psa/goeval/cost"
+	"github.com/datar-psa/goeval/experiment"
+	"github.com/datar-psa/goeval/stats"
+)
+
+// evaluate scores rows with every scorer, scoring up to concurrency rows in parallel
+// Results are returned in row order, and the token usage of every score is added to usage
+func evaluate(ctx context.Context, rows []Row, scorers []namedScorer, concurrency int, usage *cost.Accumulator) []experiment.Result {
+	results := make([]experiment.Result, len(rows))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range max(1, min(concurrency, len(rows))) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = scoreRow(ctx, rows[i], scorers, usage)
+			}
+		}()
+	}
+
+	for i := range rows {
+		if ctx.Err() != nil {
+			break
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+func scoreRow(ctx context.Context, row Row, scorers []namedScorer, usage *cost.Accumulator) experiment.Result {
+	result := experiment.Result{ID: row.ID, Tags: row.Tags, Scores: make([]experiment.ScoreResult, len(scorers))}
+	inputs := row.Inputs()
+	for i, s := range scorers {
+		score := s.scorer.Score(ctx, inputs)
+		if scoreUsage, ok := cost.UsageOf(score); ok {
+			usage.AddUsage(s.label, scoreUsage)
+		}
+		result.Scores[i] = experiment.ScoreResult{Scorer: s.label, Score: score.Score, Metadata: score.Metadata}
+		if score.Error != nil {
+			result.Scores[i].Error = score.Error.Error()
+		}
+		// Keep results writable when a scorer reports metadata that cannot be encoded
+		if _, err := json.Marshal(score.Metadata); err != nil {
+			result.Scores[i].Metadata = map[string]any{"metadata_error": err.Error()}
+		}
+	}
+	return result
+}
+
+// writeResults writes one JSON line per row, followed by a line with the run's token usage and estimated cost
+func writeResults(path string, results []experiment.Result, usage cost.Report) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create results file: %w", err)
+	}
+	if err := experiment.EncodeResults(file, results); err != nil {
+		file.Close()
+		return err
+	}
+	if err := json.NewEncoder(file).Encode(experiment.UsageLine{Usage: usage}); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write results: %w", err)
+	}
+	return file.Close()
+}
+
+// writeUsage prints the token usage and estimated cost per scorer and per model, if any scorer reported usage
+func writeUsage(w io.Writer, usage cost.Report) error {
+	if usage.Total.Calls == 0 {
+		return nil
+	}
+	fmt.Fprintln(w, "\nCOST")
+	return usage.WriteTable(w)
+}
+
+// Summary aggregates one scorer across rows; errored rows count as 0 in the mean
+type Summary struct {
+	Scorer string
+	stats.Summary
+	Threshold *float64
+	// Strata break the summary down by the values of the stratify tag, if one is set
+	Strata []stats.Stratum
+}
+
+// Passed reports whether the mean reaches the threshold, if one is set
+func (s Summary) Passed() bool {
+	return s.Threshold == nil || s.Mean >= *s.Threshold
+}
+
+func summarize(scorers []namedScorer, results []experiment.Result, stratify string) []Summary {
+	summaries := make([]Summary, len(scorers))
+	for i, s := range scorers {
+		scores := experiment.Scores(results, s.label)
+		plain := make([]api.Score, len(scores))
+		for j, score := range scores {
+			plain[j] = score.Score
+		}
+		summaries[i] = Summary{Scorer: s.label, Summary: stats.Summarize(plain, stats.SummaryOptions{}), Threshold: s.threshold}
+		if stratify != "" {
+			summaries[i].Strata = stats.SummarizeBy(scores, stratify, stats.SummaryOptions{})
+		}
+	}
+	return summaries
+}
+
+// writeSummary prints a table with one line per scorer, followed by a table per stratify tag value
+func writeSummary(w io.Writer, stratify string, summaries []Summary) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "SCORER\tMEAN\t95%% CI\tSTDERR\tMIN\tMAX\tERRORS\tTHRESHOLD\tSTATUS\n")
+	for _, s := range summaries {
+		threshold, status := "-", "ok"
+		if s.Threshold != nil {
+			threshold = fmt.Sprintf("%.3f", *s.Threshold)
+		}
+		if !s.Passed() {
+			status = "FAIL"
+		}
+		fmt.Fprintf(tw, "%s\t%.3f\t[%.3f, %.3f]\t%.3f\t%.3f\t%.3f\t%d/%d\t%s\t%s\n",
+			s.Scorer, s.Mean, s.CI.Low, s.CI.High, s.StdErr, s.Min, s.Max, s.Errors, s.N, threshold, status)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if stratify == "" {
+		return nil
+	}
+
+	fmt.Fprintf(w, "\nBY %s\n", stratify)
+	fmt.Fprintf(tw, "SCORER\t%s\tROWS\tMEAN\t95%% CI\tSTDERR\tERRORS\n", strings.ToUpper(stratify))
+	for _, s := range summaries {
+		for _, stratum := range s.Strata {
+			value := stratum.Value
+			if value == "" {
+				value = "(none)"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%.3f\t[%.3f, %.3f]\t%.3f\t%d\n", s.Scorer, value, stratum.Summary.N,
+				stratum.Summary.Mean, stratum.Summary.CI.Low, stratum.Summary.CI.High, stratum.Summary.StdErr, stratum.Summary.Errors)
+		}
+	}
+	return tw.Flush()
+}
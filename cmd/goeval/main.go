@@ -0,0 +1,156 @@
+// Command goeval runs evaluations declared in a YAML or JSON config file
+//
+// Usage:
+//
+//	goeval run -config eval.yaml [-output results.jsonl] [-concurrency 4] [-stratify tag]
+//	goeval compare [-max-regression 0.02] baseline.jsonl candidate.jsonl
+//
+// The config declares providers, scorers with their options and thresholds, and a dataset file
+// with one row per line (JSONL) or a JSON array of rows. goeval prints a summary table with
+// bootstrap confidence intervals, optionally broken down by a row tag, and the token usage and
+// estimated cost per scorer and per model. It writes per-row results as JSONL, followed by a line
+// with the usage totals, and exits with status 1 when a scorer's mean score is below its threshold.
+//
+// compare pairs the rows of two results files by ID, reports per-scorer mean deltas with paired
+// bootstrap and Wilcoxon p-values and the most regressed rows, and exits with status 1 when a
+// scorer's mean drops by more than allowed.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/datar-psa/goeval/cost"
+)
+
+// Exit codes
+const (
+	exitOK       = 0
+	exitFailed   = 1
+	exitUsage    = 2
+	exitRunError = 3
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "run":
+		return runCommand(ctx, args[1:], stdout, stderr)
+	case "compare":
+		return compareCommand(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		usage(stdout)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "goeval: unknown command %q\n\n", args[0])
+		usage(stderr)
+		return exitUsage
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  goeval run -config eval.yaml [-output results.jsonl] [-concurrency N] [-stratify tag]
+  goeval compare [flags] baseline.jsonl candidate.jsonl
+
+Commands:
+  run      score a dataset with the scorers declared in a config file
+  compare  compare two results files and fail on regressions
+`)
+}
+
+func runCommand(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configPath := flags.String("config", "", "path to the YAML or JSON config file (required)")
+	output := flags.String("output", "", "path of the JSONL results file (overrides the config)")
+	concurrency := flags.Int("concurrency", 0, "number of rows scored in parallel (overrides the config)")
+	stratify := flags.String("stratify", "", "row tag to break the summary down by (overrides the config)")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *configPath == "" {
+		fmt.Fprintln(stderr, "goeval: -config is required")
+		flags.Usage()
+		return exitUsage
+	}
+
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "goeval: %v\n", err)
+		return exitUsage
+	}
+	if *output != "" {
+		config.Output = *output
+	}
+	if *concurrency > 0 {
+		config.Concurrency = *concurrency
+	}
+	if *stratify != "" {
+		config.Stratify = *stratify
+	}
+
+	rows, err := loadDataset(config.Dataset)
+	if err != nil {
+		fmt.Fprintf(stderr, "goeval: %v\n", err)
+		return exitUsage
+	}
+
+	providers, err := newProviders(ctx, config.Providers)
+	if err != nil {
+		fmt.Fprintf(stderr, "goeval: %v\n", err)
+		return exitRunError
+	}
+	defer providers.Close()
+
+	scorers, err := buildScorers(config.Scorers, providers)
+	if err != nil {
+		fmt.Fprintf(stderr, "goeval: %v\n", err)
+		return exitUsage
+	}
+
+	usage := cost.NewAccumulator(nil)
+	results := evaluate(ctx, rows, scorers, config.Concurrency, usage)
+	if err := ctx.Err(); err != nil {
+		fmt.Fprintf(stderr, "goeval: interrupted: %v\n", err)
+		return exitRunError
+	}
+
+	if config.Output != "" {
+		if err := writeResults(config.Output, results, usage.Report()); err != nil {
+			fmt.Fprintf(stderr, "goeval: %v\n", err)
+			return exitRunError
+		}
+	}
+
+	summaries := summarize(scorers, results, config.Stratify)
+	if err := writeSummary(stdout, config.Stratify, summaries); err != nil {
+		fmt.Fprintf(stderr, "goeval: %v\n", err)
+		return exitRunError
+	}
+	if err := writeUsage(stdout, usage.Report()); err != nil {
+		fmt.Fprintf(stderr, "goeval: %v\n", err)
+		return exitRunError
+	}
+	for _, summary := range summaries {
+		if !summary.Passed() {
+			return exitFailed
+		}
+	}
+	return exitOK
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+	"github.com/datar-psa/goeval/cost"
+	"github.com/datar-psa/goeval/experiment"
+)
+
+const testDataset = `{"id": "paris", "input": "Capital of France?", "output": "Paris", "expected": "paris", "tags": {"topic": "geo"}}
+{"input": "2+2?", "output": "5", "expected": "4"}
+`
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      string
+		wantCode    int
+		wantSummary []string
+	}{
+		{
+			name: "thresholds pass",
+			config: `
+dataset: data.jsonl
+providers:
+  embedder: local
+scorers:
+  - name: ExactMatch
+    options: {case_insensitive: true, aggregation: best}
+    threshold: 0.5
+  - name: EmbeddingSimilarity
+`,
+			wantCode:    exitOK,
+			wantSummary: []string{"ExactMatch", "0.500", "[0.000, 1.000]", "ok", "EmbeddingSimilarity"},
+		},
+		{
+			name: "stratified summary",
+			config: `
+dataset: data.jsonl
+stratify: topic
+scorers:
+  - name: ExactMatch
+    options: {case_insensitive: true}
+`,
+			wantCode:    exitOK,
+			wantSummary: []string{"BY topic", "TOPIC", "geo", "1.000", "(none)"},
+		},
+		{
+			name: "threshold fails",
+			config: `
+dataset: data.jsonl
+scorers:
+  - name: ExactMatch
+    label: exact
+    threshold: 0.9
+`,
+			wantCode:    exitFailed,
+			wantSummary: []string{"exact", "0.000", "FAIL"},
+		},
+		{
+			name:     "JSON config",
+			config:   `{"dataset": "data.jsonl", "scorers": [{"name": "Regex", "options": {"Pattern": "^\\d+$"}}]}`,
+			wantCode: exitOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFile(t, dir, "data.jsonl", testDataset)
+			config := writeFile(t, dir, "eval.yaml", tt.config)
+			output := filepath.Join(dir, "results.jsonl")
+
+			var stdout, stderr bytes.Buffer
+			code := run(context.Background(), []string{"run", "-config", config, "-output", output}, &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d\nstdout:\n%s\nstderr:\n%s", code, tt.wantCode, stdout.String(), stderr.String())
+			}
+			for _, want := range tt.wantSummary {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("summary does not contain %q:\n%s", want, stdout.String())
+				}
+			}
+
+			file, err := os.Open(output)
+			if err != nil {
+				t.Fatalf("results file: %v", err)
+			}
+			defer file.Close()
+			var ids []string
+			var usage *cost.Report
+			scanner := bufio.NewScanner(file)
+			for scanner.Scan() {
+				var line struct {
+					experiment.Result
+					Usage *cost.Report `json:"usage"`
+				}
+				if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+					t.Fatalf("invalid result line %q: %v", scanner.Text(), err)
+				}
+				if line.Usage != nil {
+					usage = line.Usage
+					continue
+				}
+				ids = append(ids, line.ID)
+			}
+			if strings.Join(ids, ",") != "paris,row-2" {
+				t.Errorf("result ids = %v, want [paris row-2]", ids)
+			}
+			if usage == nil {
+				t.Error("results file has no usage line")
+			}
+		})
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{name: "unknown scorer", config: "dataset: data.jsonl\nscorers:\n  - name: Nope\n", wantErr: `unknown scorer`},
+		{name: "unknown option", config: "dataset: data.jsonl\nscorers:\n  - name: ExactMatch\n    options: {Typo: true}\n", wantErr: `Typo: unknown field`},
+		{name: "unknown config field", config: "dataset: data.jsonl\nscorer: []\n", wantErr: "field scorer not found"},
+		{name: "missing provider", config: "dataset: data.jsonl\nscorers:\n  - name: Factuality\n", wantErr: "requires an LLM generator (configure it under providers)"},
+		{name: "duplicate label", config: "dataset: data.jsonl\nscorers:\n  - name: ExactMatch\n  - name: ExactMatch\n", wantErr: "duplicate scorer"},
+		{name: "missing dataset", config: "dataset: missing.jsonl\nscorers:\n  - name: ExactMatch\n", wantErr: "failed to read dataset"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFile(t, dir, "data.jsonl", testDataset)
+			config := writeFile(t, dir, "eval.yaml", tt.config)
+
+			var stdout, stderr bytes.Buffer
+			if code := run(context.Background(), []string{"run", "-config", config}, &stdout, &stderr); code != exitUsage {
+				t.Errorf("exit code = %d, want %d", code, exitUsage)
+			}
+			if !strings.Contains(stderr.String(), tt.wantErr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr.String(), tt.wantErr)
+			}
+		})
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), nil, &stdout, &stderr); code != exitUsage {
+		t.Errorf("no command: exit code = %d, want %d", code, exitUsage)
+	}
+	if code := run(context.Background(), []string{"run"}, &stdout, &stderr); code != exitUsage {
+		t.Errorf("no config: exit code = %d, want %d", code, exitUsage)
+	}
+}
+
+// usageScorer reports fixed token usage for every row
+type usageScorer struct{ usage api.Usage }
+
+func (s usageScorer) Score(ctx context.Context, in api.ScoreInputs) api.Score {
+	return api.Score{Name: "Judge", Score: 1, Metadata: map[string]any{"usage": s.usage}}
+}
+
+func TestEvaluate_Usage(t *testing.T) {
+	rows, err := loadDataset(writeFile(t, t.TempDir(), "data.jsonl", testDataset))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scorers := []namedScorer{
+		{label: "judge", scorer: usageScorer{usage: api.Usage{Model: "gemini-2.5-flash", Calls: 1, PromptTokens: 100, OutputTokens: 10}}},
+		{label: "judge-pro", scorer: usageScorer{usage: api.Usage{Model: "gemini-2.5-pro", Calls: 1, PromptTokens: 200}}},
+	}
+
+	usage := cost.NewAccumulator(nil)
+	evaluate(context.Background(), rows, scorers, 2, usage)
+	report := usage.Report()
+	if report.Total.Calls != 4 || report.Total.PromptTokens != 600 || report.Total.OutputTokens != 20 {
+		t.Errorf("Total = %+v, want 4 calls, 600 prompt and 20 output tokens", report.Total)
+	}
+	if report.ByScorer["judge"].Calls != 2 || report.ByScorer["judge-pro"].PromptTokens != 400 {
+		t.Errorf("ByScorer = %+v, want usage under the scorer labels", report.ByScorer)
+	}
+	if report.ByModel["gemini-2.5-pro"].Calls != 2 || report.Total.Cost <= 0 {
+		t.Errorf("ByModel = %+v, Total = %+v, want priced usage per model", report.ByModel, report.Total)
+	}
+
+	var stdout bytes.Buffer
+	if err := writeUsage(&stdout, report); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"COST", "judge-pro", "gemini-2.5-flash", "total"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("usage table does not contain %q:\n%s", want, stdout.String())
+		}
+	}
+}
+
+func TestLoadDataset_JSONArray(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "data.json", `[{"output": "a"}, {"id": "b", "output": "b", "tool_calls": [{"name": "search"}]}]`)
+	rows, err := loadDataset(path)
+	if err != nil {
+		t.Fatalf("loadDataset() error = %v", err)
+	}
+	if len(rows) != 2 || rows[0].ID != "row-1" || rows[1].ID != "b" || rows[1].Inputs().ToolCalls[0].Name != "search" {
+		t.Errorf("loadDataset() = %+v", rows)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	const baseline = `{"id": "a", "scores": [{"scorer": "exact", "score": 1}]}
+{"id": "b", "scores": [{"scorer": "exact", "score": 1}]}
+`
+	const candidate = `{"id": "a", "scores": [{"scorer": "exact", "score": 1}]}
+{"id": "b", "scores": [{"scorer": "exact", "score": 0.5}]}
+`
+	tests := []struct {
+		name       string
+		flags      []string
+		wantCode   int
+		wantOutput []string
+	}{
+		{name: "regression fails", wantCode: exitFailed, wantOutput: []string{"exact", "-0.250", "FAIL", "b", "1.000 -> 0.500"}},
+		{name: "within allowance", flags: []string{"-max-regression", "0.3"}, wantCode: exitOK, wantOutput: []string{"ok"}},
+		{name: "scorer allowance", flags: []string{"-max-regression", "0.3", "-scorer-max-regression", "exact=0.1"}, wantCode: exitFailed},
+		{name: "JSON", flags: []string{"-json", "-max-regression", "0.3"}, wantCode: exitOK, wantOutput: []string{`"mean_delta": -0.25`, `"passed": true`}},
+		{name: "invalid scorer allowance", flags: []string{"-scorer-max-regression", "exact"}, wantCode: exitUsage},
+		{name: "unknown scorer allowance", flags: []string{"-scorer-max-regression", "exct=0.1"}, wantCode: exitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			args := append([]string{"compare"}, tt.flags...)
+			args = append(args, writeFile(t, dir, "baseline.jsonl", baseline), writeFile(t, dir, "candidate.jsonl", candidate))
+
+			var stdout, stderr bytes.Buffer
+			if code := run(context.Background(), args, &stdout, &stderr); code != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d\nstdout:\n%s\nstderr:\n%s", code, tt.wantCode, stdout.String(), stderr.String())
+			}
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, stdout.String())
+				}
+			}
+		})
+	}
+}
+
+func TestCompare_Errors(t *testing.T) {
+	dir := t.TempDir()
+	results := writeFile(t, dir, "results.jsonl", `{"id": "a", "scores": []}`)
+
+	for _, args := range [][]string{
+		{"compare", results},
+		{"compare", results, filepath.Join(dir, "missing.jsonl")},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(context.Background(), args, &stdout, &stderr); code != exitUsage {
+			t.Errorf("%v: exit code = %d, want %d", args, code, exitUsage)
+		}
+	}
+}
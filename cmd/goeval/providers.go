@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	language "cloud.google.com/go/language/apiv1"
+	"google.golang.org/genai"
+
+	"github.com/datar-psa/goeval/gemini"
+	"github.com/datar-psa/goeval/local"
+	"github.com/datar-psa/goeval/registry"
+)
+
+// providers holds the model clients scorers are built with; unconfigured providers are nil
+type providers struct {
+	registry.Providers
+
+	langClient *language.Client
+}
+
+// newProviders creates the clients declared in the config
+func newProviders(ctx context.Context, config ProvidersConfig) (*providers, error) {
+	p := &providers{}
+
+	if config.Gemini != nil {
+		clientConfig := &genai.ClientConfig{}
+		if config.Gemini.Project != "" {
+			clientConfig.Backend = genai.BackendVertexAI
+			clientConfig.Project = config.Gemini.Project
+			clientConfig.Location = config.Gemini.Location
+		}
+		client, err := genai.NewClient(ctx, clientConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+		}
+		if config.Gemini.Model != "" {
+			p.LLM = gemini.NewGenerator(client, config.Gemini.Model)
+		}
+		if config.Gemini.EmbeddingModel != "" && (config.Embedder == "" || config.Embedder == "gemini") {
+			p.Embedder = gemini.NewEmbedder(client, config.Gemini.EmbeddingModel)
+		}
+	}
+
+	switch config.Embedder {
+	case "", "gemini":
+		if config.Embedder == "gemini" && p.Embedder == nil {
+			return nil, fmt.Errorf("embedder gemini requires providers.gemini.embedding_model")
+		}
+	case "local":
+		p.Embedder = local.NewEmbedder()
+	default:
+		return nil, fmt.Errorf("unknown embedder %q (want gemini or local)", config.Embedder)
+	}
+
+	if config.Moderation {
+		client, err := language.NewClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Natural Language client: %w", err)
+		}
+		p.langClient = client
+		p.Moderation = gemini.NewGoogleLanguageProvider(client)
+	}
+
+	return p, nil
+}
+
+// Close releases the provider clients
+func (p *providers) Close() {
+	if p.langClient != nil {
+		p.langClient.Close()
+	}
+}
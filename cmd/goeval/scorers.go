@@ -0,0 +1,32 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/datar-psa/goeval/api"
+	"github.com/datar-psa/goeval/registry"
+)
+
+// namedScorer is a configured scorer with its reporting label and threshold
+type namedScorer struct {
+	label     string
+	threshold *float64
+	scorer    api.Scorer
+}
+
+// buildScorers builds the scorers declared in the config from the default scorer registry
+func buildScorers(configs []ScorerConfig, p *providers) ([]namedScorer, error) {
+	scorers := make([]namedScorer, 0, len(configs))
+	for _, config := range configs {
+		scorer, err := registry.Build(config.Name, p.Providers, config.Options)
+		if errors.Is(err, registry.ErrMissingProvider) {
+			err = fmt.Errorf("%w (configure it under providers)", err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("scorer %q: %w", config.label(), err)
+		}
+		scorers = append(scorers, namedScorer{label: config.label(), threshold: config.Threshold, scorer: scorer})
+	}
+	return scorers, nil
+}
@@ -0,0 +1,149 @@
+package cost
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// Totals sums token usage and estimated cost
+type Totals struct {
+	Calls          int     `json:"calls"`
+	PromptTokens   int     `json:"prompt_tokens"`
+	OutputTokens   int     `json:"output_tokens"`
+	ThinkingTokens int     `json:"thinking_tokens"`
+	Cost           float64 `json:"cost_usd"`
+	// UnpricedCalls counts calls to models missing from the pricing table; their cost is not included
+	UnpricedCalls int `json:"unpriced_calls,omitempty"`
+}
+
+// TotalTokens returns the sum of prompt, output and thinking tokens
+func (t Totals) TotalTokens() int {
+	return t.PromptTokens + t.OutputTokens + t.ThinkingTokens
+}
+
+func (t *Totals) add(usage api.Usage, cost float64, priced bool) {
+	t.Calls += usage.Calls
+	t.PromptTokens += usage.PromptTokens
+	t.OutputTokens += usage.OutputTokens
+	t.ThinkingTokens += usage.ThinkingTokens
+	t.Cost += cost
+	if !priced {
+		t.UnpricedCalls += usage.Calls
+	}
+}
+
+// Report is a snapshot of accumulated usage
+type Report struct {
+	Total    Totals            `json:"total"`
+	ByScorer map[string]Totals `json:"by_scorer"`
+	ByModel  map[string]Totals `json:"by_model"`
+}
+
+// Accumulator collects token usage across scores and estimates cost per scorer and per model
+// It is safe for concurrent use
+type Accumulator struct {
+	pricing PricingTable
+
+	mu       sync.Mutex
+	total    Totals
+	byScorer map[string]*Totals
+	byModel  map[string]*Totals
+}
+
+// NewAccumulator creates an accumulator using pricing (default: DefaultPricing)
+func NewAccumulator(pricing PricingTable) *Accumulator {
+	if pricing == nil {
+		pricing = DefaultPricing
+	}
+	return &Accumulator{
+		pricing:  pricing,
+		byScorer: make(map[string]*Totals),
+		byModel:  make(map[string]*Totals),
+	}
+}
+
+// Add records the usage attached to a score's metadata, if any
+func (a *Accumulator) Add(score api.Score) {
+	if usage, ok := UsageOf(score); ok {
+		a.AddUsage(score.Name, usage)
+	}
+}
+
+// AddUsage records usage for a scorer
+func (a *Accumulator) AddUsage(scorer string, usage api.Usage) {
+	cost, priced := a.pricing.Cost(usage)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.total.add(usage, cost, priced)
+	totals(a.byScorer, scorer).add(usage, cost, priced)
+	totals(a.byModel, usage.Model).add(usage, cost, priced)
+}
+
+// Report returns a snapshot of the accumulated totals
+func (a *Accumulator) Report() Report {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	report := Report{
+		Total:    a.total,
+		ByScorer: make(map[string]Totals, len(a.byScorer)),
+		ByModel:  make(map[string]Totals, len(a.byModel)),
+	}
+	for name, t := range a.byScorer {
+		report.ByScorer[name] = *t
+	}
+	for name, t := range a.byModel {
+		report.ByModel[name] = *t
+	}
+	return report
+}
+
+// WriteTable writes the report as a human-readable table
+func (r Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "GROUP\tNAME\tCALLS\tPROMPT\tOUTPUT\tTHINKING\tCOST (USD)")
+	writeRows := func(group string, rows map[string]Totals) {
+		names := make([]string, 0, len(rows))
+		for name := range rows {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			writeRow(tw, group, name, rows[name])
+		}
+	}
+	writeRows("scorer", r.ByScorer)
+	writeRows("model", r.ByModel)
+	writeRow(tw, "total", "", r.Total)
+	return tw.Flush()
+}
+
+func writeRow(w io.Writer, group, name string, t Totals) {
+	cost := fmt.Sprintf("%.4f", t.Cost)
+	if t.UnpricedCalls > 0 {
+		cost += fmt.Sprintf(" (%d unpriced)", t.UnpricedCalls)
+	}
+	fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%s\n", group, name, t.Calls, t.PromptTokens, t.OutputTokens, t.ThinkingTokens, cost)
+}
+
+// UsageOf returns the token usage recorded in a score's metadata
+func UsageOf(score api.Score) (api.Usage, bool) {
+	usage, ok := score.Metadata["usage"].(api.Usage)
+	return usage, ok
+}
+
+func totals(m map[string]*Totals, key string) *Totals {
+	t, ok := m[key]
+	if !ok {
+		t = &Totals{}
+		m[key] = t
+	}
+	return t
+}
@@ -0,0 +1,64 @@
+package cost
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+func TestAccumulator(t *testing.T) {
+	acc := NewAccumulator(PricingTable{"judge-model": {InputPerMillion: 1, OutputPerMillion: 2}})
+
+	scores := []api.Score{
+		{Name: "Factuality", Metadata: map[string]any{"usage": api.Usage{Model: "judge-model", Calls: 1, PromptTokens: 1000, OutputTokens: 100}}},
+		{Name: "Factuality", Metadata: map[string]any{"usage": api.Usage{Model: "judge-model", Calls: 2, PromptTokens: 2000, OutputTokens: 200, ThinkingTokens: 50}}},
+		{Name: "Tonality", Metadata: map[string]any{"usage": api.Usage{Model: "other-model", Calls: 1, PromptTokens: 500}}},
+		{Name: "ExactMatch", Metadata: map[string]any{}},
+	}
+
+	var wg sync.WaitGroup
+	for _, score := range scores {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			acc.Add(score)
+		}()
+	}
+	wg.Wait()
+
+	report := acc.Report()
+
+	if report.Total.Calls != 4 || report.Total.TotalTokens() != 3850 {
+		t.Errorf("Total = %+v, want 4 calls and 3850 tokens", report.Total)
+	}
+	wantCost := (3000*1 + 350*2) / 1e6
+	if math.Abs(report.Total.Cost-wantCost) > 1e-12 {
+		t.Errorf("Total.Cost = %v, want %v", report.Total.Cost, wantCost)
+	}
+	if report.Total.UnpricedCalls != 1 {
+		t.Errorf("Total.UnpricedCalls = %v, want 1", report.Total.UnpricedCalls)
+	}
+
+	if got := report.ByScorer["Factuality"]; got.Calls != 3 || got.ThinkingTokens != 50 {
+		t.Errorf("ByScorer[Factuality] = %+v", got)
+	}
+	if _, ok := report.ByScorer["ExactMatch"]; ok {
+		t.Error("ByScorer contains a scorer without usage")
+	}
+	if got := report.ByModel["other-model"]; got.Calls != 1 || got.Cost != 0 || got.UnpricedCalls != 1 {
+		t.Errorf("ByModel[other-model] = %+v", got)
+	}
+
+	var sb strings.Builder
+	if err := report.WriteTable(&sb); err != nil {
+		t.Fatalf("WriteTable() error = %v", err)
+	}
+	for _, want := range []string{"scorer  Factuality", "model   judge-model", "(1 unpriced)", "total"} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("WriteTable() output missing %q:\n%s", want, sb.String())
+		}
+	}
+}
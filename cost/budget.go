@@ -0,0 +1,237 @@
+package cost
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// BudgetOptions configures a Budget; zero values mean no limit
+type BudgetOptions struct {
+	// MaxCalls is the maximum number of provider calls across all guarded providers
+	MaxCalls int
+	// MaxTokens is the maximum number of tokens reported by guarded generators
+	MaxTokens int
+	// MaxCost is the maximum estimated cost in USD of guarded generators
+	MaxCost float64
+	// Pricing is used to estimate cost (default: DefaultPricing)
+	Pricing PricingTable
+}
+
+// BudgetExceededError reports which limit of a Budget was reached
+// It matches api.ErrBudgetExceeded with errors.Is
+type BudgetExceededError struct {
+	// Limit is "calls", "tokens" or "cost"
+	Limit string
+	// Max is the configured limit
+	Max float64
+	// Used is the amount spent when the call was refused
+	Used float64
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("budget exceeded: %s limit %g reached (used %g)", e.Limit, e.Max, e.Used)
+}
+
+// Is reports whether target is api.ErrBudgetExceeded
+func (e *BudgetExceededError) Is(target error) bool {
+	return target == api.ErrBudgetExceeded
+}
+
+// Budget enforces call, token and cost limits for a run across the providers it guards
+// Calls are refused with a *BudgetExceededError once a limit is reached, so scorers fail fast
+// Token and cost limits are checked before each call against usage reported so far,
+// so concurrent in-flight calls may overshoot them slightly
+type Budget struct {
+	opts BudgetOptions
+
+	mu    sync.Mutex
+	spent Totals
+}
+
+// NewBudget creates a Budget with the given limits
+func NewBudget(opts BudgetOptions) *Budget {
+	if opts.Pricing == nil {
+		opts.Pricing = DefaultPricing
+	}
+	return &Budget{opts: opts}
+}
+
+// Spent returns the calls, tokens and estimated cost recorded so far
+func (b *Budget) Spent() Totals {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.spent
+}
+
+// reserve checks the limits and counts a call, or returns a *BudgetExceededError
+func (b *Budget) reserve() error {
+	return b.reserveCalls(1)
+}
+
+// reserveCalls checks the limits and counts n calls at once, or returns a *BudgetExceededError
+// The calls are refused together when they would go over MaxCalls
+func (b *Budget) reserveCalls(n int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case b.opts.MaxCalls > 0 && b.spent.Calls+n > b.opts.MaxCalls:
+		return &BudgetExceededError{Limit: "calls", Max: float64(b.opts.MaxCalls), Used: float64(b.spent.Calls)}
+	case b.opts.MaxTokens > 0 && b.spent.TotalTokens() >= b.opts.MaxTokens:
+		return &BudgetExceededError{Limit: "tokens", Max: float64(b.opts.MaxTokens), Used: float64(b.spent.TotalTokens())}
+	case b.opts.MaxCost > 0 && b.spent.Cost >= b.opts.MaxCost:
+		return &BudgetExceededError{Limit: "cost", Max: b.opts.MaxCost, Used: b.spent.Cost}
+	}
+	b.spent.Calls += n
+	return nil
+}
+
+// record adds the tokens and cost of a completed call; the call itself was counted by reserve
+func (b *Budget) record(usage api.Usage) {
+	cost, priced := b.opts.Pricing.Cost(usage)
+	usage.Calls = 0
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.spent.add(usage, cost, true)
+	if !priced && usage.TotalTokens() > 0 {
+		b.spent.UnpricedCalls++
+	}
+}
+
+// Generator wraps llm so that its calls count against the budget
+// Tokens and cost are tracked when llm implements api.UsageReportingGenerator
+func (b *Budget) Generator(llm api.LLMGenerator) api.LLMGenerator {
+	return &budgetGenerator{budget: b, llm: llm}
+}
+
+// Embedder wraps embedder so that its calls count against the budget
+func (b *Budget) Embedder(embedder api.Embedder) api.Embedder {
+	return &budgetEmbedder{budget: b, embedder: embedder}
+}
+
+// ModerationProvider wraps provider so that its calls count against the budget
+func (b *Budget) ModerationProvider(provider api.ModerationProvider) api.ModerationProvider {
+	return &budgetModerationProvider{budget: b, provider: provider}
+}
+
+type budgetGenerator struct {
+	budget *Budget
+	llm    api.LLMGenerator
+}
+
+func (g *budgetGenerator) StructuredGenerate(ctx context.Context, prompt string, schema map[string]interface{}) (map[string]interface{}, error) {
+	result, err := g.StructuredGenerateContentWithUsage(ctx, []api.Part{api.TextPart(prompt)}, schema)
+	return result.Data, err
+}
+
+func (g *budgetGenerator) StructuredGenerateContent(ctx context.Context, parts []api.Part, schema map[string]interface{}) (map[string]interface{}, error) {
+	result, err := g.StructuredGenerateContentWithUsage(ctx, parts, schema)
+	return result.Data, err
+}
+
+func (g *budgetGenerator) StructuredGenerateContentWithUsage(ctx context.Context, parts []api.Part, schema map[string]interface{}) (api.GenerateResult, error) {
+	// Refuse multimodal content before spending budget if the wrapped generator cannot handle it
+	reporting, isReporting := g.llm.(api.UsageReportingGenerator)
+	multimodal, isMultimodal := g.llm.(api.MultimodalGenerator)
+	textOnly := len(parts) == 1 && len(parts[0].Data) == 0
+	if !isReporting && !isMultimodal && !textOnly {
+		return api.GenerateResult{}, api.ErrMultimodalNotSupported
+	}
+
+	if err := g.budget.reserve(); err != nil {
+		return api.GenerateResult{}, err
+	}
+
+	switch {
+	case isReporting:
+		result, err := reporting.StructuredGenerateContentWithUsage(ctx, parts, schema)
+		g.budget.record(result.Usage)
+		return result, err
+	case textOnly:
+		data, err := g.llm.StructuredGenerate(ctx, parts[0].Text, schema)
+		return api.GenerateResult{Data: data, Usage: g.callUsage()}, err
+	default:
+		data, err := multimodal.StructuredGenerateContent(ctx, parts, schema)
+		return api.GenerateResult{Data: data, Usage: g.callUsage()}, err
+	}
+}
+
+// callUsage is the usage of one call to a generator that does not report tokens
+// The call is attributed to the model from the generator's settings, if it reports them
+func (g *budgetGenerator) callUsage() api.Usage {
+	usage := api.Usage{Calls: 1}
+	if model, ok := g.GenerationConfig()["model"].(string); ok {
+		usage.Model = model
+	}
+	return usage
+}
+
+// GenerationConfig passes through the wrapped generator's settings, if it reports them
+func (g *budgetGenerator) GenerationConfig() map[string]any {
+	if reporter, ok := g.llm.(api.GenerationConfigReporter); ok {
+		return reporter.GenerationConfig()
+	}
+	return nil
+}
+
+type budgetEmbedder struct {
+	budget   *Budget
+	embedder api.Embedder
+}
+
+func (e *budgetEmbedder) Embed(ctx context.Context, text string) ([]float64, error) {
+	if err := e.budget.reserve(); err != nil {
+		return nil, err
+	}
+	return e.embedder.Embed(ctx, text)
+}
+
+// EmbedBatch counts one call per provider request
+// Batch embedders that do not implement api.BatchRequestCounter may split a batch into a request per text,
+// so their batches count one call per text; embedders that cannot batch are called once per text
+func (e *budgetEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float64, error) {
+	if batch, ok := e.embedder.(api.BatchEmbedder); ok {
+		requests := len(texts)
+		if counter, ok := batch.(api.BatchRequestCounter); ok {
+			requests = counter.BatchRequests(texts)
+		}
+		if err := e.budget.reserveCalls(requests); err != nil {
+			return nil, err
+		}
+		return batch.EmbedBatch(ctx, texts)
+	}
+
+	embeddings := make([][]float64, len(texts))
+	for i, text := range texts {
+		embedding, err := e.Embed(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		embeddings[i] = embedding
+	}
+	return embeddings, nil
+}
+
+type budgetModerationProvider struct {
+	budget   *Budget
+	provider api.ModerationProvider
+}
+
+func (p *budgetModerationProvider) Moderate(ctx context.Context, content string) (*api.ModerationResult, error) {
+	if err := p.budget.reserve(); err != nil {
+		return nil, err
+	}
+	return p.provider.Moderate(ctx, content)
+}
+
+// Verify that the guarded generator and embedder keep the extended interfaces
+var (
+	_ api.BatchEmbedder            = (*budgetEmbedder)(nil)
+	_ api.MultimodalGenerator      = (*budgetGenerator)(nil)
+	_ api.UsageReportingGenerator  = (*budgetGenerator)(nil)
+	_ api.GenerationConfigReporter = (*budgetGenerator)(nil)
+)
@@ -0,0 +1,291 @@
+package cost
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+	"github.com/datar-psa/goeval/gemini"
+	"google.golang.org/genai"
+)
+
+// mockGenerator reports fixed usage for every call
+type mockGenerator struct {
+	usage api.Usage
+	calls int
+}
+
+func (m *mockGenerator) StructuredGenerate(ctx context.Context, prompt string, schema map[string]interface{}) (map[string]interface{}, error) {
+	m.calls++
+	return map[string]interface{}{"ok": true}, nil
+}
+
+func (m *mockGenerator) StructuredGenerateContentWithUsage(ctx context.Context, parts []api.Part, schema map[string]interface{}) (api.GenerateResult, error) {
+	m.calls++
+	return api.GenerateResult{Data: map[string]interface{}{"ok": true}, Usage: m.usage}, nil
+}
+
+// textGenerator only implements the base interface
+type textGenerator struct{ calls int }
+
+func (m *textGenerator) StructuredGenerate(ctx context.Context, prompt string, schema map[string]interface{}) (map[string]interface{}, error) {
+	m.calls++
+	return map[string]interface{}{"ok": true}, nil
+}
+
+// configuredGenerator reports its settings but not usage
+type configuredGenerator struct{ textGenerator }
+
+func (m *configuredGenerator) GenerationConfig() map[string]any {
+	return map[string]any{"model": "m", "temperature": 0.0}
+}
+
+type mockEmbedder struct{ calls int }
+
+func (m *mockEmbedder) Embed(ctx context.Context, text string) ([]float64, error) {
+	m.calls++
+	return []float64{1}, nil
+}
+
+type mockModerationProvider struct{ calls int }
+
+func (m *mockModerationProvider) Moderate(ctx context.Context, content string) (*api.ModerationResult, error) {
+	m.calls++
+	return &api.ModerationResult{}, nil
+}
+
+func TestBudget_Limits(t *testing.T) {
+	ctx := context.Background()
+	usage := api.Usage{Model: "m", Calls: 1, PromptTokens: 600, OutputTokens: 400}
+	pricing := PricingTable{"m": {InputPerMillion: 1000, OutputPerMillion: 1000}} // $1 per call
+
+	tests := []struct {
+		name      string
+		opts      BudgetOptions
+		wantCalls int
+		wantLimit string
+	}{
+		{name: "max calls", opts: BudgetOptions{MaxCalls: 3}, wantCalls: 3, wantLimit: "calls"},
+		{name: "max tokens", opts: BudgetOptions{MaxTokens: 2500}, wantCalls: 3, wantLimit: "tokens"},
+		{name: "max cost", opts: BudgetOptions{MaxCost: 2, Pricing: pricing}, wantCalls: 2, wantLimit: "cost"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &mockGenerator{usage: usage}
+			llm := NewBudget(tt.opts).Generator(inner)
+
+			var err error
+			for range 10 {
+				if _, err = llm.StructuredGenerate(ctx, "prompt", nil); err != nil {
+					break
+				}
+			}
+
+			if inner.calls != tt.wantCalls {
+				t.Errorf("wrapped generator calls = %d, want %d", inner.calls, tt.wantCalls)
+			}
+			if !errors.Is(err, api.ErrBudgetExceeded) {
+				t.Fatalf("error = %v, want api.ErrBudgetExceeded", err)
+			}
+			var budgetErr *BudgetExceededError
+			if !errors.As(err, &budgetErr) || budgetErr.Limit != tt.wantLimit {
+				t.Errorf("error = %#v, want limit %q", err, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestBudget_SharedAcrossProviders(t *testing.T) {
+	ctx := context.Background()
+	budget := NewBudget(BudgetOptions{MaxCalls: 3})
+
+	llm := &textGenerator{}
+	embedder := &mockEmbedder{}
+	moderation := &mockModerationProvider{}
+
+	if _, err := budget.Generator(llm).StructuredGenerate(ctx, "p", nil); err != nil {
+		t.Fatalf("StructuredGenerate() error = %v", err)
+	}
+	if _, err := budget.Embedder(embedder).Embed(ctx, "t"); err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if _, err := budget.ModerationProvider(moderation).Moderate(ctx, "c"); err != nil {
+		t.Fatalf("Moderate() error = %v", err)
+	}
+	if _, err := budget.Embedder(embedder).Embed(ctx, "t"); !errors.Is(err, api.ErrBudgetExceeded) {
+		t.Errorf("Embed() error = %v, want api.ErrBudgetExceeded", err)
+	}
+
+	if embedder.calls != 1 || llm.calls != 1 || moderation.calls != 1 {
+		t.Errorf("calls = %d/%d/%d, want 1 each", llm.calls, embedder.calls, moderation.calls)
+	}
+	if got := budget.Spent().Calls; got != 3 {
+		t.Errorf("Spent().Calls = %d, want 3", got)
+	}
+}
+
+func TestBudget_MultimodalWithTextGenerator(t *testing.T) {
+	budget := NewBudget(BudgetOptions{MaxCalls: 1})
+	llm := budget.Generator(&textGenerator{}).(api.MultimodalGenerator)
+
+	_, err := llm.StructuredGenerateContent(context.Background(), []api.Part{api.TextPart("p"), api.ImagePart([]byte{1}, "image/png")}, nil)
+	if !errors.Is(err, api.ErrMultimodalNotSupported) {
+		t.Errorf("StructuredGenerateContent() error = %v, want api.ErrMultimodalNotSupported", err)
+	}
+	if got := budget.Spent().Calls; got != 0 {
+		t.Errorf("Spent().Calls = %d, want 0 for a refused call", got)
+	}
+}
+
+type mockBatchEmbedder struct {
+	mockEmbedder
+	batches int
+}
+
+func (m *mockBatchEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float64, error) {
+	m.batches++
+	embeddings := make([][]float64, len(texts))
+	for i := range texts {
+		embeddings[i] = []float64{1}
+	}
+	return embeddings, nil
+}
+
+// countingBatchEmbedder reports that it sends a single request per batch
+type countingBatchEmbedder struct{ mockBatchEmbedder }
+
+func (m *countingBatchEmbedder) BatchRequests(texts []string) int {
+	return 1
+}
+
+func TestBudget_EmbedBatch(t *testing.T) {
+	ctx := context.Background()
+	texts := []string{"a", "b", "c"}
+
+	budget := NewBudget(BudgetOptions{})
+	counting := &countingBatchEmbedder{}
+	if _, err := budget.Embedder(counting).(api.BatchEmbedder).EmbedBatch(ctx, texts); err != nil {
+		t.Fatalf("EmbedBatch() error = %v", err)
+	}
+	if counting.batches != 1 || counting.calls != 0 {
+		t.Errorf("batches/calls = %d/%d, want 1/0", counting.batches, counting.calls)
+	}
+	if got := budget.Spent().Calls; got != 1 {
+		t.Errorf("Spent().Calls = %d, want 1 for a single-request batch", got)
+	}
+
+	// Without a request count a batch may be sent one text at a time
+	budget = NewBudget(BudgetOptions{})
+	batch := &mockBatchEmbedder{}
+	if _, err := budget.Embedder(batch).(api.BatchEmbedder).EmbedBatch(ctx, texts); err != nil {
+		t.Fatalf("EmbedBatch() error = %v", err)
+	}
+	if got := budget.Spent().Calls; got != 3 {
+		t.Errorf("Spent().Calls = %d, want 3 for a batch without a request count", got)
+	}
+
+	budget = NewBudget(BudgetOptions{MaxCalls: 2})
+	batch = &mockBatchEmbedder{}
+	if _, err := budget.Embedder(batch).(api.BatchEmbedder).EmbedBatch(ctx, texts); !errors.Is(err, api.ErrBudgetExceeded) {
+		t.Errorf("EmbedBatch() error = %v, want api.ErrBudgetExceeded", err)
+	}
+	if batch.batches != 0 {
+		t.Errorf("batches = %d, want 0 when the batch does not fit the budget", batch.batches)
+	}
+
+	budget = NewBudget(BudgetOptions{MaxCalls: 2})
+	single := &mockEmbedder{}
+	if _, err := budget.Embedder(single).(api.BatchEmbedder).EmbedBatch(ctx, texts); !errors.Is(err, api.ErrBudgetExceeded) {
+		t.Errorf("EmbedBatch() error = %v, want api.ErrBudgetExceeded", err)
+	}
+	if single.calls != 2 {
+		t.Errorf("calls = %d, want 2 before the budget ran out", single.calls)
+	}
+}
+
+func TestBudget_GeminiEmbedder(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Requests []json.RawMessage `json:"requests"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		requests++
+		embeddings := make([]map[string]any, len(body.Requests))
+		for i := range embeddings {
+			embeddings[i] = map[string]any{"values": []float32{1, 0}}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"embeddings": embeddings})
+	}))
+	defer server.Close()
+
+	client, err := genai.NewClient(t.Context(), &genai.ClientConfig{
+		APIKey:      "test",
+		Backend:     genai.BackendGeminiAPI,
+		HTTPOptions: genai.HTTPOptions{BaseURL: server.URL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	texts := []string{"a", "b", "c"}
+
+	// The default batch size sends one request per text, so a batch of three needs three calls
+	budget := NewBudget(BudgetOptions{MaxCalls: 2})
+	embedder := budget.Embedder(gemini.NewEmbedder(client, "gemini-embedding-001")).(api.BatchEmbedder)
+	if _, err := embedder.EmbedBatch(t.Context(), texts); !errors.Is(err, api.ErrBudgetExceeded) {
+		t.Errorf("EmbedBatch() error = %v, want api.ErrBudgetExceeded", err)
+	}
+	if requests != 0 {
+		t.Errorf("requests = %d, want 0 after the batch was refused", requests)
+	}
+
+	budget = NewBudget(BudgetOptions{MaxCalls: 3})
+	embedder = budget.Embedder(gemini.NewEmbedder(client, "gemini-embedding-001")).(api.BatchEmbedder)
+	if _, err := embedder.EmbedBatch(t.Context(), texts); err != nil {
+		t.Fatalf("EmbedBatch() error = %v", err)
+	}
+	if got := budget.Spent().Calls; got != 3 || requests != 3 {
+		t.Errorf("Spent().Calls = %d for %d requests, want 3 for 3", got, requests)
+	}
+
+	requests = 0
+	budget = NewBudget(BudgetOptions{})
+	embedder = budget.Embedder(gemini.NewEmbedder(client, "text-embedding-005", gemini.WithMaxBatchSize(100))).(api.BatchEmbedder)
+	if _, err := embedder.EmbedBatch(t.Context(), texts); err != nil {
+		t.Fatalf("EmbedBatch() error = %v", err)
+	}
+	if got := budget.Spent().Calls; got != 1 || requests != 1 {
+		t.Errorf("Spent().Calls = %d for %d requests, want 1 for 1", got, requests)
+	}
+}
+
+func TestBudget_UsageWithoutReporting(t *testing.T) {
+	tests := []struct {
+		name      string
+		llm       api.LLMGenerator
+		wantModel string
+	}{
+		{name: "model from generation config", llm: &configuredGenerator{}, wantModel: "m"},
+		{name: "no generation config", llm: &textGenerator{}, wantModel: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := NewBudget(BudgetOptions{}).Generator(tt.llm).(api.UsageReportingGenerator)
+			result, err := llm.StructuredGenerateContentWithUsage(context.Background(), []api.Part{api.TextPart("p")}, nil)
+			if err != nil {
+				t.Fatalf("StructuredGenerateContentWithUsage() error = %v", err)
+			}
+			if want := (api.Usage{Model: tt.wantModel, Calls: 1}); result.Usage != want {
+				t.Errorf("Usage = %+v, want %+v", result.Usage, want)
+			}
+		})
+	}
+}
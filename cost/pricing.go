@@ -0,0 +1,70 @@
+// Package cost estimates the cost of eval runs from the token usage reported by providers
+package cost
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// Price is the cost of a model in USD per million tokens
+type Price struct {
+	// InputPerMillion is the price of prompt tokens
+	InputPerMillion float64
+	// OutputPerMillion is the price of response tokens
+	OutputPerMillion float64
+	// ThinkingPerMillion is the price of thinking tokens (default: OutputPerMillion)
+	ThinkingPerMillion float64
+}
+
+// PricingTable maps model names to prices
+// Names are matched against the last path segment of a model (e.g. "publishers/google/models/gemini-2.5-flash"),
+// exactly or as the longest prefix, so versioned names like "gemini-2.5-flash-001" find "gemini-2.5-flash"
+type PricingTable map[string]Price
+
+// DefaultPricing lists public list prices at the time of writing
+// Prices change and vary by contract, so copy and adjust the table for accurate accounting
+var DefaultPricing = PricingTable{
+	"gemini-2.5-pro":        {InputPerMillion: 1.25, OutputPerMillion: 10.00},
+	"gemini-2.5-flash":      {InputPerMillion: 0.30, OutputPerMillion: 2.50},
+	"gemini-2.5-flash-lite": {InputPerMillion: 0.10, OutputPerMillion: 0.40},
+	"gemini-2.0-flash":      {InputPerMillion: 0.10, OutputPerMillion: 0.40},
+	"gemini-2.0-flash-lite": {InputPerMillion: 0.075, OutputPerMillion: 0.30},
+}
+
+// Lookup returns the price for a model
+func (t PricingTable) Lookup(model string) (Price, bool) {
+	name := model[strings.LastIndex(model, "/")+1:]
+	if price, ok := t[name]; ok {
+		return price, true
+	}
+
+	// Longest prefix wins, so "gemini-2.5-flash-lite-001" does not match "gemini-2.5-flash"
+	keys := make([]string, 0, len(t))
+	for key := range t {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+	for _, key := range keys {
+		if strings.HasPrefix(name, key) {
+			return t[key], true
+		}
+	}
+	return Price{}, false
+}
+
+// Cost returns the estimated cost of usage in USD, and false if the model has no price
+func (t PricingTable) Cost(usage api.Usage) (float64, bool) {
+	price, ok := t.Lookup(usage.Model)
+	if !ok {
+		return 0, false
+	}
+	thinking := price.ThinkingPerMillion
+	if thinking == 0 {
+		thinking = price.OutputPerMillion
+	}
+	return (float64(usage.PromptTokens)*price.InputPerMillion +
+		float64(usage.OutputTokens)*price.OutputPerMillion +
+		float64(usage.ThinkingTokens)*thinking) / 1e6, true
+}
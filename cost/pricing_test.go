@@ -0,0 +1,71 @@
+package cost
+
+import (
+	"math"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+func TestPricingTable_Lookup(t *testing.T) {
+	tests := []struct {
+		model  string
+		want   Price
+		wantOK bool
+	}{
+		{model: "gemini-2.5-flash", want: DefaultPricing["gemini-2.5-flash"], wantOK: true},
+		{model: "publishers/google/models/gemini-2.5-flash", want: DefaultPricing["gemini-2.5-flash"], wantOK: true},
+		{model: "gemini-2.5-flash-001", want: DefaultPricing["gemini-2.5-flash"], wantOK: true},
+		{model: "gemini-2.5-flash-lite-preview", want: DefaultPricing["gemini-2.5-flash-lite"], wantOK: true},
+		{model: "my-fine-tune", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			got, ok := DefaultPricing.Lookup(tt.model)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("Lookup(%q) = %v, %v, want %v, %v", tt.model, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPricingTable_Cost(t *testing.T) {
+	table := PricingTable{
+		"m":        {InputPerMillion: 1, OutputPerMillion: 4},
+		"thinking": {InputPerMillion: 1, OutputPerMillion: 4, ThinkingPerMillion: 2},
+	}
+
+	tests := []struct {
+		name   string
+		usage  api.Usage
+		want   float64
+		wantOK bool
+	}{
+		{
+			name:   "thinking billed as output by default",
+			usage:  api.Usage{Model: "m", PromptTokens: 1_000_000, OutputTokens: 500_000, ThinkingTokens: 250_000},
+			want:   1 + 2 + 1,
+			wantOK: true,
+		},
+		{
+			name:   "separate thinking price",
+			usage:  api.Usage{Model: "thinking", ThinkingTokens: 1_000_000},
+			want:   2,
+			wantOK: true,
+		},
+		{
+			name:  "unknown model",
+			usage: api.Usage{Model: "other", PromptTokens: 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := table.Cost(tt.usage)
+			if ok != tt.wantOK || math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("Cost() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
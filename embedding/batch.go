@@ -0,0 +1,48 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// embedTexts embeds texts in order, in a single batch when the embedder implements api.BatchEmbedder
+// Duplicate texts are embedded once
+func embedTexts(ctx context.Context, embedder api.Embedder, texts []string) ([][]float64, error) {
+	unique := make([]string, 0, len(texts))
+	positions := make(map[string]int, len(texts))
+	for _, text := range texts {
+		if _, ok := positions[text]; !ok {
+			positions[text] = len(unique)
+			unique = append(unique, text)
+		}
+	}
+
+	var uniqueEmbeddings [][]float64
+	if batch, ok := embedder.(api.BatchEmbedder); ok {
+		embeddings, err := batch.EmbedBatch(ctx, unique)
+		if err != nil {
+			return nil, err
+		}
+		if len(embeddings) != len(unique) {
+			return nil, fmt.Errorf("expected %d embeddings, got %d", len(unique), len(embeddings))
+		}
+		uniqueEmbeddings = embeddings
+	} else {
+		uniqueEmbeddings = make([][]float64, len(unique))
+		for i, text := range unique {
+			embedding, err := embedder.Embed(ctx, text)
+			if err != nil {
+				return nil, err
+			}
+			uniqueEmbeddings[i] = embedding
+		}
+	}
+
+	embeddings := make([][]float64, len(texts))
+	for i, text := range texts {
+		embeddings[i] = uniqueEmbeddings[positions[text]]
+	}
+	return embeddings, nil
+}
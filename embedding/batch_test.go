@@ -0,0 +1,69 @@
+package embedding
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+type mockBatchEmbedder struct {
+	mockEmbedder
+	batches [][]string
+}
+
+func (m *mockBatchEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float64, error) {
+	m.batches = append(m.batches, texts)
+	embeddings := make([][]float64, len(texts))
+	for i, text := range texts {
+		embedding, err := m.Embed(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		embeddings[i] = embedding
+	}
+	return embeddings, nil
+}
+
+func TestEmbedTexts_Batch(t *testing.T) {
+	embedder := &mockBatchEmbedder{mockEmbedder: mockEmbedder{embeddings: map[string][]float64{
+		"a": {1, 0},
+		"b": {0, 1},
+	}}}
+
+	got, err := embedTexts(context.Background(), embedder, []string{"a", "b", "a"})
+	if err != nil {
+		t.Fatalf("embedTexts() error = %v", err)
+	}
+	want := [][]float64{{1, 0}, {0, 1}, {1, 0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("embedTexts() = %v, want %v", got, want)
+	}
+	if wantBatches := [][]string{{"a", "b"}}; !reflect.DeepEqual(embedder.batches, wantBatches) {
+		t.Errorf("batches = %v, want %v (one batch of unique texts)", embedder.batches, wantBatches)
+	}
+}
+
+func TestEmbeddingSimilarity_UsesBatch(t *testing.T) {
+	embedder := &mockBatchEmbedder{mockEmbedder: mockEmbedder{embeddings: map[string][]float64{
+		"output": {1, 0},
+		"ref1":   {0, 1},
+		"ref2":   {1, 0},
+	}}}
+
+	result := EmbeddingSimilarity(embedder, EmbeddingSimilarityOptions{}).Score(context.Background(), api.ScoreInputs{
+		Output:               "output",
+		Expected:             "ref1",
+		ExpectedAlternatives: []string{"ref2"},
+	})
+	if result.Error != nil {
+		t.Fatalf("Score() error = %v", result.Error)
+	}
+	if len(embedder.batches) != 1 {
+		t.Errorf("batches = %d, want 1", len(embedder.batches))
+	}
+	if result.Score != 1 {
+		t.Errorf("Score = %v, want 1", result.Score)
+	}
+}
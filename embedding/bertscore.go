@@ -0,0 +1,254 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// Granularity selects the text units BERTScore embeds and aligns
+type Granularity int
+
+const (
+	// GranularityToken aligns individual words (default)
+	GranularityToken Granularity = iota
+	// GranularityPhrase aligns clauses separated by punctuation
+	GranularityPhrase
+	// GranularitySentence aligns sentences
+	GranularitySentence
+)
+
+// String returns the granularity name used in metadata; a custom Splitter is reported as "custom"
+func (g Granularity) String() string {
+	switch g {
+	case GranularityPhrase:
+		return "phrase"
+	case GranularitySentence:
+		return "sentence"
+	default:
+		return "token"
+	}
+}
+
+// BERTScoreOptions configures the BERTScore scorer
+type BERTScoreOptions struct {
+	// Granularity selects the units that are embedded and aligned (default: GranularityToken)
+	Granularity Granularity
+	// Splitter splits Output and Expected into units, overriding Granularity (optional)
+	Splitter Splitter
+	// Aggregation combines results across Expected and ExpectedAlternatives (default: api.AggregateBest)
+	Aggregation api.ReferenceAggregation
+	// Calibration rescales precision, recall and F1 against a model baseline, like BERTScore's baseline
+	// rescaling (optional, e.g. Baseline(0.7)). Without it raw cosine similarities are reported, which
+	// sit high (often 0.7-0.9) even for unrelated text with modern embedding models
+	Calibration *Calibration
+}
+
+// UnitMatch is the most similar unit of the other text for one unit
+type UnitMatch struct {
+	Unit       string  `json:"unit"`
+	Match      string  `json:"match"`
+	Similarity float64 `json:"similarity"`
+}
+
+// BERTScore returns a scorer in the style of BERTScore that catches partial overlaps
+// Output and Expected are split into units (tokens, phrases or sentences) which are embedded;
+// each unit is greedily aligned with its most similar unit of the other text.
+// Precision averages the best similarities of output units, recall those of expected units,
+// and the score is their F1. Batch-capable embedders embed all units in one call
+func BERTScore(embedder api.Embedder, opts BERTScoreOptions) api.Scorer {
+	return &bertScoreScorer{
+		opts:     opts,
+		embedder: embedder,
+	}
+}
+
+type bertScoreScorer struct {
+	opts     BERTScoreOptions
+	embedder api.Embedder
+}
+
+type bertScoreResult struct {
+	precision, recall, f1          float64
+	rawPrecision, rawRecall, rawF1 float64
+	outputMatches, expectedMatches []UnitMatch
+	expectedUnits                  int
+}
+
+// rescale maps precision, recall and F1 through calibration, keeping the raw values
+func (r bertScoreResult) rescale(calibration Calibration) bertScoreResult {
+	r.rawPrecision, r.rawRecall, r.rawF1 = r.precision, r.recall, r.f1
+	r.precision = calibration.Rescale(r.precision)
+	r.recall = calibration.Rescale(r.recall)
+	r.f1 = calibration.Rescale(r.f1)
+	return r
+}
+
+func (s *bertScoreScorer) Score(ctx context.Context, in api.ScoreInputs) api.Score {
+	result := api.Score{
+		Name:     "BERTScore",
+		Metadata: make(map[string]any),
+	}
+
+	references := in.References()
+	if len(references) == 0 {
+		result.Error = api.ErrNoExpectedValue
+		result.Score = 0
+		return result
+	}
+
+	if s.embedder == nil {
+		result.Error = fmt.Errorf("embedder is required")
+		result.Score = 0
+		return result
+	}
+
+	if s.opts.Calibration != nil {
+		if err := s.opts.Calibration.validate(); err != nil {
+			result.Error = err
+			result.Score = 0
+			return result
+		}
+	}
+
+	split, granularity := s.opts.Splitter, "custom"
+	if split == nil {
+		split, granularity = granularitySplitter(s.opts.Granularity), s.opts.Granularity.String()
+	}
+
+	outputUnits := split(in.Output)
+	referenceUnits := make([][]string, len(references))
+	texts := append([]string{}, outputUnits...)
+	for i, reference := range references {
+		referenceUnits[i] = split(reference)
+		if len(referenceUnits[i]) == 0 {
+			result.Error = api.ErrNoExpectedValue
+			result.Score = 0
+			return result
+		}
+		texts = append(texts, referenceUnits[i]...)
+	}
+
+	// Embed the units of the output and all references together, in one batch when the embedder supports it
+	embeddings, err := embedTexts(ctx, s.embedder, texts)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to embed text units: %w", err)
+		result.Score = 0
+		return result
+	}
+	outputEmbeds := embeddings[:len(outputUnits)]
+
+	results := make([]bertScoreResult, len(references))
+	scores := make([]float64, len(references))
+	offset := len(outputUnits)
+	for i, units := range referenceUnits {
+		results[i] = bertScoreAlign(outputUnits, outputEmbeds, units, embeddings[offset:offset+len(units)])
+		if s.opts.Calibration != nil {
+			results[i] = results[i].rescale(*s.opts.Calibration)
+		}
+		scores[i] = results[i].f1
+		offset += len(units)
+	}
+
+	score, index := s.opts.Aggregation.Aggregate(scores)
+	best := results[index]
+
+	result.Score = score
+	result.Metadata["precision"] = best.precision
+	result.Metadata["recall"] = best.recall
+	result.Metadata["f1"] = best.f1
+	result.Metadata["granularity"] = granularity
+	result.Metadata["output_units"] = len(outputUnits)
+	result.Metadata["expected_units"] = best.expectedUnits
+	result.Metadata["output_matches"] = best.outputMatches
+	result.Metadata["expected_matches"] = best.expectedMatches
+	result.Metadata["reference_index"] = index
+	if len(references) > 1 {
+		result.Metadata["reference_scores"] = scores
+	}
+	if s.opts.Calibration != nil {
+		result.Metadata["raw_precision"] = best.rawPrecision
+		result.Metadata["raw_recall"] = best.rawRecall
+		result.Metadata["raw_f1"] = best.rawF1
+		result.Metadata["calibration"] = Calibration{Min: s.opts.Calibration.Min, Max: s.opts.Calibration.max()}
+	}
+
+	return result
+}
+
+// bertScoreAlign greedily matches every unit with its most similar unit on the other side
+// Negative similarities count as 0
+func bertScoreAlign(outputUnits []string, outputEmbeds [][]float64, expectedUnits []string, expectedEmbeds [][]float64) bertScoreResult {
+	similarities := make([][]float64, len(outputUnits))
+	for i := range outputUnits {
+		similarities[i] = make([]float64, len(expectedUnits))
+		for j := range expectedUnits {
+			similarities[i][j] = clamp(cosineSimilarity(outputEmbeds[i], expectedEmbeds[j]))
+		}
+	}
+
+	res := bertScoreResult{
+		outputMatches:   make([]UnitMatch, len(outputUnits)),
+		expectedMatches: make([]UnitMatch, len(expectedUnits)),
+		expectedUnits:   len(expectedUnits),
+	}
+	for i, unit := range outputUnits {
+		res.outputMatches[i] = UnitMatch{Unit: unit}
+		for j, match := range expectedUnits {
+			if similarities[i][j] > res.outputMatches[i].Similarity || res.outputMatches[i].Match == "" {
+				res.outputMatches[i].Match = match
+				res.outputMatches[i].Similarity = similarities[i][j]
+			}
+		}
+		res.precision += res.outputMatches[i].Similarity
+	}
+	for j, unit := range expectedUnits {
+		res.expectedMatches[j] = UnitMatch{Unit: unit}
+		for i, match := range outputUnits {
+			if similarities[i][j] > res.expectedMatches[j].Similarity || res.expectedMatches[j].Match == "" {
+				res.expectedMatches[j].Match = match
+				res.expectedMatches[j].Similarity = similarities[i][j]
+			}
+		}
+		res.recall += res.expectedMatches[j].Similarity
+	}
+
+	if len(outputUnits) > 0 {
+		res.precision /= float64(len(outputUnits))
+	}
+	res.recall /= float64(len(expectedUnits))
+	if res.precision+res.recall > 0 {
+		res.f1 = 2 * res.precision * res.recall / (res.precision + res.recall)
+	}
+	return res
+}
+
+var (
+	tokenPattern    = regexp.MustCompile(`[\p{L}\p{N}]+(?:['’][\p{L}]+)?`)
+	phrasePattern   = regexp.MustCompile(`[,;:.!?\n]+`)
+	sentencePattern = regexp.MustCompile(`[.!?]+(?:\s+|$)|\n+`)
+)
+
+func granularitySplitter(granularity Granularity) Splitter {
+	switch granularity {
+	case GranularityPhrase:
+		return func(text string) []string { return splitTrimmed(phrasePattern, text) }
+	case GranularitySentence:
+		return func(text string) []string { return splitTrimmed(sentencePattern, text) }
+	default:
+		return func(text string) []string { return tokenPattern.FindAllString(strings.ToLower(text), -1) }
+	}
+}
+
+func splitTrimmed(pattern *regexp.Regexp, text string) []string {
+	var units []string
+	for _, unit := range pattern.Split(text, -1) {
+		if unit = strings.TrimSpace(unit); unit != "" {
+			units = append(units, unit)
+		}
+	}
+	return units
+}
@@ -0,0 +1,200 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+func TestBERTScore_Unit(t *testing.T) {
+	// Orthogonal one-hot vectors: identical units score 1, different units 0
+	embedder := &mockBatchEmbedder{mockEmbedder: mockEmbedder{embeddings: map[string][]float64{
+		"the":   {1, 0, 0, 0, 0},
+		"cat":   {0, 1, 0, 0, 0},
+		"sat":   {0, 0, 1, 0, 0},
+		"dog":   {0, 0, 0, 1, 0},
+		"kitty": {0, 0.8, 0, 0, 0.6},
+	}}}
+
+	tests := []struct {
+		name          string
+		output        string
+		expected      string
+		wantPrecision float64
+		wantRecall    float64
+	}{
+		{name: "identical", output: "The cat sat", expected: "the cat sat", wantPrecision: 1, wantRecall: 1},
+		{name: "partial overlap", output: "the dog", expected: "the cat sat", wantPrecision: 0.5, wantRecall: 1.0 / 3},
+		{name: "extra output tokens", output: "the cat sat the dog", expected: "the cat", wantPrecision: 0.6, wantRecall: 1},
+		{name: "similar token", output: "the kitty", expected: "the cat", wantPrecision: 0.9, wantRecall: 0.9},
+		{name: "empty output", output: "", expected: "the cat", wantPrecision: 0, wantRecall: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := BERTScore(embedder, BERTScoreOptions{}).Score(context.Background(), api.ScoreInputs{Output: tt.output, Expected: tt.expected})
+			if result.Error != nil {
+				t.Fatalf("Score() error = %v", result.Error)
+			}
+			precision := result.Metadata["precision"].(float64)
+			recall := result.Metadata["recall"].(float64)
+			if math.Abs(precision-tt.wantPrecision) > 1e-9 || math.Abs(recall-tt.wantRecall) > 1e-9 {
+				t.Errorf("precision/recall = %v/%v, want %v/%v", precision, recall, tt.wantPrecision, tt.wantRecall)
+			}
+			wantF1 := 0.0
+			if tt.wantPrecision+tt.wantRecall > 0 {
+				wantF1 = 2 * tt.wantPrecision * tt.wantRecall / (tt.wantPrecision + tt.wantRecall)
+			}
+			if math.Abs(result.Score-wantF1) > 1e-9 {
+				t.Errorf("Score = %v, want %v", result.Score, wantF1)
+			}
+		})
+	}
+
+	t.Run("single batch", func(t *testing.T) {
+		embedder.batches = nil
+		BERTScore(embedder, BERTScoreOptions{}).Score(context.Background(), api.ScoreInputs{
+			Output:               "the cat",
+			Expected:             "the dog",
+			ExpectedAlternatives: []string{"cat sat"},
+		})
+		if want := [][]string{{"the", "cat", "dog", "sat"}}; !reflect.DeepEqual(embedder.batches, want) {
+			t.Errorf("batches = %v, want %v", embedder.batches, want)
+		}
+	})
+}
+
+func TestBERTScore_MultipleReferences(t *testing.T) {
+	embedder := &mockEmbedder{embeddings: map[string][]float64{
+		"a": {1, 0, 0},
+		"b": {0, 1, 0},
+		"c": {0, 0, 1},
+	}}
+
+	result := BERTScore(embedder, BERTScoreOptions{}).Score(context.Background(), api.ScoreInputs{
+		Output:               "a b",
+		Expected:             "c",
+		ExpectedAlternatives: []string{"a b"},
+	})
+	if result.Error != nil {
+		t.Fatalf("Score() error = %v", result.Error)
+	}
+	if result.Score != 1 || result.Metadata["reference_index"] != 1 {
+		t.Errorf("Score = %v, reference_index = %v, want 1 and 1", result.Score, result.Metadata["reference_index"])
+	}
+}
+
+func TestBERTScore_Calibration(t *testing.T) {
+	embedder := &mockEmbedder{embeddings: map[string][]float64{
+		"the": {1, 0, 0, 0},
+		"cat": {0, 1, 0, 0},
+		"sat": {0, 0, 1, 0},
+		"dog": {0, 0, 0, 1},
+	}}
+	in := api.ScoreInputs{Output: "the dog", Expected: "the cat sat"}
+
+	// Raw precision 0.5, recall 1/3 and F1 0.4 are rescaled from [0.2, 1] to [0, 1]
+	baseline := Baseline(0.2)
+	result := BERTScore(embedder, BERTScoreOptions{Calibration: &baseline}).Score(context.Background(), in)
+	if result.Error != nil {
+		t.Fatalf("Score() error = %v", result.Error)
+	}
+	for key, want := range map[string]float64{
+		"precision":     0.375,
+		"recall":        (1.0/3 - 0.2) / 0.8,
+		"f1":            0.25,
+		"raw_precision": 0.5,
+		"raw_recall":    1.0 / 3,
+		"raw_f1":        0.4,
+	} {
+		if got := result.Metadata[key].(float64); math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", key, got, want)
+		}
+	}
+	if math.Abs(result.Score-0.25) > 1e-9 {
+		t.Errorf("Score = %v, want 0.25", result.Score)
+	}
+
+	invalid := Calibration{Min: 1}
+	result = BERTScore(embedder, BERTScoreOptions{Calibration: &invalid}).Score(context.Background(), in)
+	if result.Error == nil {
+		t.Error("invalid calibration: error = nil, want error")
+	}
+}
+
+func TestBERTScore_GranularityMetadata(t *testing.T) {
+	embedder := &mockEmbedder{embeddings: map[string][]float64{"a": {1, 0}, "b": {0, 1}, "a b": {1, 1}}}
+	in := api.ScoreInputs{Output: "a b", Expected: "a b"}
+
+	tests := []struct {
+		name string
+		opts BERTScoreOptions
+		want string
+	}{
+		{name: "default", want: "token"},
+		{name: "sentence", opts: BERTScoreOptions{Granularity: GranularitySentence}, want: "sentence"},
+		{name: "custom splitter", opts: BERTScoreOptions{Granularity: GranularitySentence, Splitter: strings.Fields}, want: "custom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := BERTScore(embedder, tt.opts).Score(context.Background(), in)
+			if result.Error != nil {
+				t.Fatalf("Score() error = %v", result.Error)
+			}
+			if got := result.Metadata["granularity"]; got != tt.want {
+				t.Errorf("granularity = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBERTScore_Errors(t *testing.T) {
+	embedder := &mockEmbedder{}
+
+	result := BERTScore(embedder, BERTScoreOptions{}).Score(context.Background(), api.ScoreInputs{Output: "x"})
+	if !errors.Is(result.Error, api.ErrNoExpectedValue) {
+		t.Errorf("no expected: error = %v, want ErrNoExpectedValue", result.Error)
+	}
+
+	result = BERTScore(embedder, BERTScoreOptions{}).Score(context.Background(), api.ScoreInputs{Output: "x", Expected: "..."})
+	if !errors.Is(result.Error, api.ErrNoExpectedValue) {
+		t.Errorf("no expected units: error = %v, want ErrNoExpectedValue", result.Error)
+	}
+
+	result = BERTScore(nil, BERTScoreOptions{}).Score(context.Background(), api.ScoreInputs{Output: "x", Expected: "y"})
+	if result.Error == nil {
+		t.Error("nil embedder: error = nil, want error")
+	}
+
+	failing := &mockEmbedder{err: errors.New("boom")}
+	result = BERTScore(failing, BERTScoreOptions{}).Score(context.Background(), api.ScoreInputs{Output: "x", Expected: "y"})
+	if result.Error == nil || result.Score != 0 {
+		t.Errorf("embed error: Score = %v, error = %v, want 0 and error", result.Score, result.Error)
+	}
+}
+
+func TestGranularitySplitter(t *testing.T) {
+	text := "The cat's hat, it is red. Is it? Yes!\nNew line"
+	tests := []struct {
+		granularity Granularity
+		want        []string
+	}{
+		{GranularityToken, []string{"the", "cat's", "hat", "it", "is", "red", "is", "it", "yes", "new", "line"}},
+		{GranularityPhrase, []string{"The cat's hat", "it is red", "Is it", "Yes", "New line"}},
+		{GranularitySentence, []string{"The cat's hat, it is red", "Is it", "Yes", "New line"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.granularity.String(), func(t *testing.T) {
+			if got := granularitySplitter(tt.granularity)(text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("split() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
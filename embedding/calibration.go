@@ -0,0 +1,103 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// Calibration rescales raw cosine similarity so that scores are comparable across embedding models
+// Modern embedding models rarely produce low similarities, so unrelated texts still score high
+// when cosine similarity is mapped from [-1, 1] to [0, 1]
+type Calibration struct {
+	// Min is the cosine similarity that maps to 0, e.g. the model's typical similarity of unrelated texts
+	Min float64 `json:"min"`
+	// Max is the cosine similarity that maps to 1 (default: 1)
+	Max float64 `json:"max"`
+}
+
+// Baseline returns a Calibration that rescales cosine similarity from [baseline, 1] to [0, 1]
+// baseline is the model's typical cosine similarity of unrelated texts
+func Baseline(baseline float64) Calibration {
+	return Calibration{Min: baseline, Max: 1}
+}
+
+// Rescale maps a cosine similarity into [0, 1], clamping values outside [Min, Max]
+func (c Calibration) Rescale(similarity float64) float64 {
+	maxSimilarity := c.max()
+	if maxSimilarity <= c.Min {
+		return 0
+	}
+	return clamp((similarity - c.Min) / (maxSimilarity - c.Min))
+}
+
+func (c Calibration) max() float64 {
+	if c.Max == 0 {
+		return 1
+	}
+	return c.Max
+}
+
+func (c Calibration) validate() error {
+	if c.max() <= c.Min {
+		return fmt.Errorf("invalid calibration: max (%v) must be greater than min (%v)", c.max(), c.Min)
+	}
+	return nil
+}
+
+// CalibrationPair is a labeled pair of texts from a calibration dataset
+type CalibrationPair struct {
+	A string `json:"a"`
+	B string `json:"b"`
+	// Similar marks pairs that should score 1, e.g. paraphrases; other pairs should score 0
+	Similar bool `json:"similar"`
+}
+
+// Calibrate learns a Calibration for embedder from labeled pairs
+// Min is the mean cosine similarity of dissimilar pairs and Max the mean of similar pairs,
+// so at least one pair of each kind is required
+func Calibrate(ctx context.Context, embedder api.Embedder, pairs []CalibrationPair) (Calibration, error) {
+	if embedder == nil {
+		return Calibration{}, fmt.Errorf("embedder is required")
+	}
+
+	texts := make([]string, 0, 2*len(pairs))
+	for _, pair := range pairs {
+		texts = append(texts, pair.A, pair.B)
+	}
+	embeddings, err := embedTexts(ctx, embedder, texts)
+	if err != nil {
+		return Calibration{}, fmt.Errorf("failed to embed calibration pairs: %w", err)
+	}
+
+	var similarSum, dissimilarSum float64
+	var similarCount, dissimilarCount int
+	for i, pair := range pairs {
+		similarity := cosineSimilarity(embeddings[2*i], embeddings[2*i+1])
+		if pair.Similar {
+			similarSum += similarity
+			similarCount++
+		} else {
+			dissimilarSum += similarity
+			dissimilarCount++
+		}
+	}
+	if similarCount == 0 || dissimilarCount == 0 {
+		return Calibration{}, fmt.Errorf("calibration requires both similar and dissimilar pairs, got %d similar and %d dissimilar", similarCount, dissimilarCount)
+	}
+
+	calibration := Calibration{
+		Min: dissimilarSum / float64(dissimilarCount),
+		Max: similarSum / float64(similarCount),
+	}
+	if err := calibration.validate(); err != nil {
+		return Calibration{}, fmt.Errorf("similar pairs do not score higher than dissimilar pairs: %w", err)
+	}
+	return calibration, nil
+}
+
+func clamp(score float64) float64 {
+	return math.Max(0, math.Min(1, score))
+}
@@ -0,0 +1,107 @@
+package embedding
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+func TestCalibration_Rescale(t *testing.T) {
+	tests := []struct {
+		name        string
+		calibration Calibration
+		similarity  float64
+		want        float64
+	}{
+		{name: "baseline maps to zero", calibration: Baseline(0.6), similarity: 0.6, want: 0},
+		{name: "midpoint", calibration: Baseline(0.6), similarity: 0.8, want: 0.5},
+		{name: "identical", calibration: Baseline(0.6), similarity: 1, want: 1},
+		{name: "below baseline clamped", calibration: Baseline(0.6), similarity: 0.2, want: 0},
+		{name: "default max", calibration: Calibration{Min: 0.5}, similarity: 0.75, want: 0.5},
+		{name: "above max clamped", calibration: Calibration{Min: 0.5, Max: 0.9}, similarity: 0.95, want: 1},
+		{name: "invalid range", calibration: Calibration{Min: 0.9, Max: 0.5}, similarity: 0.7, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.calibration.Rescale(tt.similarity); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Rescale(%v) = %v, want %v", tt.similarity, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmbeddingSimilarity_Calibration(t *testing.T) {
+	// cos(output, expected) = 0.8
+	embedder := &mockEmbedder{embeddings: map[string][]float64{
+		"output":   {1, 0},
+		"expected": {0.8, 0.6},
+	}}
+	in := api.ScoreInputs{Output: "output", Expected: "expected"}
+
+	calibration := Baseline(0.6)
+	result := EmbeddingSimilarity(embedder, EmbeddingSimilarityOptions{Calibration: &calibration}).Score(context.Background(), in)
+	if result.Error != nil {
+		t.Fatalf("Score() error = %v", result.Error)
+	}
+	if math.Abs(result.Score-0.5) > 1e-9 {
+		t.Errorf("Score = %v, want 0.5", result.Score)
+	}
+	if got := result.Metadata["cosine_similarity"].(float64); math.Abs(got-0.8) > 1e-9 {
+		t.Errorf("cosine_similarity = %v, want 0.8", got)
+	}
+	if got := result.Metadata["rescaled_similarity"].(float64); math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("rescaled_similarity = %v, want 0.5", got)
+	}
+	if got := result.Metadata["calibration"]; got != calibration {
+		t.Errorf("calibration = %v, want %v", got, calibration)
+	}
+
+	invalid := Calibration{Min: 1}
+	result = EmbeddingSimilarity(embedder, EmbeddingSimilarityOptions{Calibration: &invalid}).Score(context.Background(), in)
+	if result.Error == nil || result.Score != 0 {
+		t.Errorf("Score() = %v, %v, want error for invalid calibration", result.Score, result.Error)
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	ctx := context.Background()
+	embedder := &mockEmbedder{embeddings: map[string][]float64{
+		"a": {1, 0},
+		"b": {0.9, math.Sqrt(1 - 0.81)}, // cos(a, b) = 0.9
+		"c": {0.7, math.Sqrt(1 - 0.49)}, // cos(a, c) = 0.7
+		"d": {0.5, math.Sqrt(1 - 0.25)}, // cos(a, d) = 0.5
+	}}
+
+	t.Run("learns min and max", func(t *testing.T) {
+		got, err := Calibrate(ctx, embedder, []CalibrationPair{
+			{A: "a", B: "b", Similar: true},
+			{A: "a", B: "c", Similar: false},
+			{A: "a", B: "d", Similar: false},
+		})
+		if err != nil {
+			t.Fatalf("Calibrate() error = %v", err)
+		}
+		if math.Abs(got.Min-0.6) > 1e-9 || math.Abs(got.Max-0.9) > 1e-9 {
+			t.Errorf("Calibrate() = %+v, want {Min: 0.6, Max: 0.9}", got)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		pairs []CalibrationPair
+	}{
+		{name: "no dissimilar pairs", pairs: []CalibrationPair{{A: "a", B: "b", Similar: true}}},
+		{name: "no similar pairs", pairs: []CalibrationPair{{A: "a", B: "d"}}},
+		{name: "similar pairs score lower", pairs: []CalibrationPair{{A: "a", B: "d", Similar: true}, {A: "a", B: "b"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Calibrate(ctx, embedder, tt.pairs); err == nil {
+				t.Error("Calibrate() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/datar-psa/goeval/api"
+	"github.com/datar-psa/goeval/internal/assignment"
+)
+
+// Splitter splits text into list items
+type Splitter func(text string) []string
+
+// ListMatchOptions configures the ListMatch scorer
+type ListMatchOptions struct {
+	// Threshold is the minimum cosine similarity for a matched pair to count as correct (default: 0.8)
+	Threshold float64
+	// Splitter splits Output and Expected into items (default: DefaultListSplitter)
+	Splitter Splitter
+}
+
+// ListItemMatch describes one pair of items chosen by the optimal assignment
+type ListItemMatch struct {
+	OutputIndex   int     `json:"output_index"`
+	ExpectedIndex int     `json:"expected_index"`
+	Output        string  `json:"output"`
+	Expected      string  `json:"expected"`
+	Similarity    float64 `json:"similarity"`
+	// Matched is true when Similarity reaches the threshold
+	Matched bool `json:"matched"`
+}
+
+// ListMatch returns a scorer that compares list-like answers regardless of order and wording
+// Output and Expected are split into items, each item is embedded, and an optimal one-to-one
+// assignment over the cosine similarity matrix is computed. Pairs at or above the threshold count
+// as matches; the score is the F1 of matched items, with precision and recall in metadata
+func ListMatch(embedder api.Embedder, opts ListMatchOptions) api.Scorer {
+	return &listMatchScorer{
+		opts:     opts,
+		embedder: embedder,
+	}
+}
+
+type listMatchScorer struct {
+	opts     ListMatchOptions
+	embedder api.Embedder
+}
+
+func (s *listMatchScorer) Score(ctx context.Context, in api.ScoreInputs) api.Score {
+	result := api.Score{
+		Name:     "ListMatch",
+		Metadata: make(map[string]any),
+	}
+
+	if in.Expected == "" {
+		result.Error = api.ErrNoExpectedValue
+		result.Score = 0
+		return result
+	}
+
+	if s.embedder == nil {
+		result.Error = fmt.Errorf("embedder is required")
+		result.Score = 0
+		return result
+	}
+
+	threshold := s.opts.Threshold
+	if threshold <= 0 {
+		threshold = 0.8
+	}
+	split := s.opts.Splitter
+	if split == nil {
+		split = DefaultListSplitter
+	}
+
+	outputItems := split(in.Output)
+	expectedItems := split(in.Expected)
+	if len(expectedItems) == 0 {
+		result.Error = api.ErrNoExpectedValue
+		result.Score = 0
+		return result
+	}
+
+	// Embed all items together, in one batch when the embedder supports it
+	embeds, err := embedTexts(ctx, s.embedder, append(append([]string{}, outputItems...), expectedItems...))
+	if err != nil {
+		result.Error = fmt.Errorf("failed to embed list items: %w", err)
+		result.Score = 0
+		return result
+	}
+	outputEmbeds, expectedEmbeds := embeds[:len(outputItems)], embeds[len(outputItems):]
+
+	similarities := make([][]float64, len(outputItems))
+	for i := range outputItems {
+		similarities[i] = make([]float64, len(expectedItems))
+		for j := range expectedItems {
+			similarities[i][j] = cosineSimilarity(outputEmbeds[i], expectedEmbeds[j])
+		}
+	}
+
+	matches := []ListItemMatch{}
+	matchedOutput := make([]bool, len(outputItems))
+	matchedExpected := make([]bool, len(expectedItems))
+	truePositives := 0
+	for i, j := range assignment.Optimal(similarities) {
+		if j < 0 {
+			continue
+		}
+		match := ListItemMatch{
+			OutputIndex:   i,
+			ExpectedIndex: j,
+			Output:        outputItems[i],
+			Expected:      expectedItems[j],
+			Similarity:    similarities[i][j],
+			Matched:       similarities[i][j] >= threshold,
+		}
+		if match.Matched {
+			truePositives++
+			matchedOutput[i] = true
+			matchedExpected[j] = true
+		}
+		matches = append(matches, match)
+	}
+
+	var precision, recall, f1 float64
+	if len(outputItems) > 0 {
+		precision = float64(truePositives) / float64(len(outputItems))
+	}
+	recall = float64(truePositives) / float64(len(expectedItems))
+	if precision+recall > 0 {
+		f1 = 2 * precision * recall / (precision + recall)
+	}
+
+	result.Score = f1
+	result.Metadata["precision"] = precision
+	result.Metadata["recall"] = recall
+	result.Metadata["f1"] = f1
+	result.Metadata["threshold"] = threshold
+	result.Metadata["matches"] = matches
+	result.Metadata["unmatched_output"] = unmatchedItems(outputItems, matchedOutput)
+	result.Metadata["unmatched_expected"] = unmatchedItems(expectedItems, matchedExpected)
+	result.Metadata["output_items"] = len(outputItems)
+	result.Metadata["expected_items"] = len(expectedItems)
+
+	return result
+}
+
+var listMarkerPattern = regexp.MustCompile(`^\s*(?:[-*•]|\d+[.)])\s+`)
+
+// DefaultListSplitter splits text into items by line, stripping bullets and numbering
+// Single-line text is split on commas and semicolons, dropping a conjunction that opens an item
+// (", and"/", or"); "and"/"or" inside an item are kept, so "salt and pepper, oil" has two items
+func DefaultListSplitter(text string) []string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	single := len(lines) == 1
+	if single {
+		lines = strings.FieldsFunc(lines[0], func(r rune) bool { return r == ',' || r == ';' })
+	}
+
+	items := make([]string, 0, len(lines))
+	for i, line := range lines {
+		item := strings.TrimSpace(listMarkerPattern.ReplaceAllString(line, ""))
+		if single && i > 0 {
+			item = listConjunctionPattern.ReplaceAllString(item, "")
+		}
+		item = strings.TrimRight(item, ".")
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+var listConjunctionPattern = regexp.MustCompile(`^(?:and|or)\s+`)
+
+func unmatchedItems(items []string, matched []bool) []string {
+	unmatched := []string{}
+	for i, item := range items {
+		if !matched[i] {
+			unmatched = append(unmatched, item)
+		}
+	}
+	return unmatched
+}
@@ -0,0 +1,139 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+func TestListMatch_Unit(t *testing.T) {
+	ctx := context.Background()
+
+	embeddings := map[string][]float64{
+		"headache":     {1.0, 0.0, 0.0, 0.0},
+		"headaches":    {0.98, 0.2, 0.0, 0.0},
+		"nausea":       {0.0, 1.0, 0.0, 0.0},
+		"feeling sick": {0.1, 0.95, 0.0, 0.0},
+		"dizziness":    {0.0, 0.0, 1.0, 0.0},
+		"hair loss":    {0.0, 0.0, 0.0, 1.0},
+	}
+
+	tests := []struct {
+		name          string
+		opts          ListMatchOptions
+		embedErr      error
+		output        string
+		expected      string
+		wantErr       bool
+		wantScore     float64
+		wantPrecision float64
+		wantRecall    float64
+	}{
+		{
+			name:          "same items different order and wording",
+			output:        "- feeling sick\n- headaches\n- dizziness",
+			expected:      "headache, nausea, and dizziness",
+			wantScore:     1.0,
+			wantPrecision: 1.0,
+			wantRecall:    1.0,
+		},
+		{
+			name:          "missing and extra items",
+			output:        "1. headache\n2. hair loss",
+			expected:      "headache, nausea, dizziness",
+			wantScore:     2 * 0.5 * (1.0 / 3.0) / (0.5 + 1.0/3.0),
+			wantPrecision: 0.5,
+			wantRecall:    1.0 / 3.0,
+		},
+		{
+			name:          "threshold rejects weak pairs",
+			opts:          ListMatchOptions{Threshold: 0.999},
+			output:        "headaches, nausea",
+			expected:      "headache, nausea",
+			wantScore:     0.5,
+			wantPrecision: 0.5,
+			wantRecall:    0.5,
+		},
+		{
+			name:          "empty output",
+			output:        "",
+			expected:      "headache",
+			wantScore:     0.0,
+			wantPrecision: 0.0,
+			wantRecall:    0.0,
+		},
+		{
+			name:     "no expected value",
+			output:   "headache",
+			expected: "",
+			wantErr:  true,
+		},
+		{
+			name:     "embedder error",
+			embedErr: fmt.Errorf("API error"),
+			output:   "headache",
+			expected: "nausea",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scorer := ListMatch(&mockEmbedder{embeddings: embeddings, err: tt.embedErr}, tt.opts)
+			result := scorer.Score(ctx, api.ScoreInputs{Output: tt.output, Expected: tt.expected})
+
+			if (result.Error != nil) != tt.wantErr {
+				t.Fatalf("ListMatch.Score() error = %v, wantErr %v", result.Error, tt.wantErr)
+			}
+			if math.Abs(result.Score-tt.wantScore) > 1e-9 {
+				t.Errorf("ListMatch.Score() score = %v, wantScore %v", result.Score, tt.wantScore)
+			}
+			if result.Name != "ListMatch" {
+				t.Errorf("ListMatch.Score() name = %v, want 'ListMatch'", result.Name)
+			}
+			if tt.wantErr {
+				return
+			}
+			if p, _ := result.Metadata["precision"].(float64); math.Abs(p-tt.wantPrecision) > 1e-9 {
+				t.Errorf("ListMatch.Score() precision = %v, want %v", p, tt.wantPrecision)
+			}
+			if r, _ := result.Metadata["recall"].(float64); math.Abs(r-tt.wantRecall) > 1e-9 {
+				t.Errorf("ListMatch.Score() recall = %v, want %v", r, tt.wantRecall)
+			}
+		})
+	}
+}
+
+func TestListMatch_NoEmbedder(t *testing.T) {
+	result := ListMatch(nil, ListMatchOptions{}).Score(context.Background(), api.ScoreInputs{Output: "a", Expected: "b"})
+
+	if result.Error == nil {
+		t.Error("ListMatch.Score() expected error when Embedder is nil")
+	}
+}
+
+func TestDefaultListSplitter(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{text: "headache, nausea, and dizziness", want: []string{"headache", "nausea", "dizziness"}},
+		{text: "salt and pepper, oil", want: []string{"salt and pepper", "oil"}},
+		{text: "oil, salt and pepper", want: []string{"oil", "salt and pepper"}},
+		{text: "tea or coffee", want: []string{"tea or coffee"}},
+		{text: "headache; nausea; or dizziness.", want: []string{"headache", "nausea", "dizziness"}},
+		{text: "1. Headache.\n2) Nausea\n\n* Dizziness", want: []string{"Headache", "Nausea", "Dizziness"}},
+		{text: "- a, b\n- c", want: []string{"a, b", "c"}},
+		{text: "  ", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := DefaultListSplitter(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("DefaultListSplitter(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -10,11 +10,16 @@ import (
 
 // EmbeddingSimilarityOptions configures the EmbeddingSimilarity scorer
 type EmbeddingSimilarityOptions struct {
-	// Additional configuration options can be added here
+	// Aggregation combines results across Expected and ExpectedAlternatives (default: api.AggregateBest)
+	Aggregation api.ReferenceAggregation
+	// Calibration rescales cosine similarity against a model baseline or a calibration dataset (optional)
+	// By default cosine similarity is mapped from [-1, 1] to [0, 1]; see Baseline and Calibrate
+	Calibration *Calibration
 }
 
 // EmbeddingSimilarity returns a scorer that measures semantic similarity using embeddings
 // It computes cosine similarity between the output and expected text embeddings
+// When ExpectedAlternatives are given, the output is compared against every reference
 func EmbeddingSimilarity(embedder api.Embedder, opts EmbeddingSimilarityOptions) api.Scorer {
 	return &embeddingSimilarityScorer{
 		opts:     opts,
@@ -33,7 +38,8 @@ func (s *embeddingSimilarityScorer) Score(ctx context.Context, in api.ScoreInput
 		Metadata: make(map[string]any),
 	}
 
-	if in.Expected == "" {
+	references := in.References()
+	if len(references) == 0 {
 		result.Error = api.ErrNoExpectedValue
 		result.Score = 0
 		return result
@@ -45,42 +51,62 @@ func (s *embeddingSimilarityScorer) Score(ctx context.Context, in api.ScoreInput
 		return result
 	}
 
-	// Generate embeddings
-	outputEmbed, err := s.embedder.Embed(ctx, in.Output)
-	if err != nil {
-		result.Error = fmt.Errorf("failed to embed output: %w", err)
-		result.Score = 0
-		return result
+	if s.opts.Calibration != nil {
+		if err := s.opts.Calibration.validate(); err != nil {
+			result.Error = err
+			result.Score = 0
+			return result
+		}
 	}
 
-	expectedEmbed, err := s.embedder.Embed(ctx, in.Expected)
+	// Embed the output and all references together, in one batch when the embedder supports it
+	embeddings, err := embedTexts(ctx, s.embedder, append([]string{in.Output}, references...))
 	if err != nil {
-		result.Error = fmt.Errorf("failed to embed expected: %w", err)
+		result.Error = fmt.Errorf("failed to embed output and expected: %w", err)
 		result.Score = 0
 		return result
 	}
+	outputEmbed := embeddings[0]
 
-	// Calculate cosine similarity
-	similarity := cosineSimilarity(outputEmbed, expectedEmbed)
+	similarities := make([]float64, len(references))
+	scores := make([]float64, len(references))
+	for i := range references {
+		expectedEmbed := embeddings[i+1]
 
-	// Normalize from [-1, 1] to [0, 1]
-	// In practice, embeddings are usually positive, so similarity is typically in [0, 1]
-	// But we handle the full range for robustness
-	normalizedScore := (similarity + 1.0) / 2.0
-	if normalizedScore < 0 {
-		normalizedScore = 0
-	}
-	if normalizedScore > 1 {
-		normalizedScore = 1
+		// Calculate cosine similarity
+		similarities[i] = cosineSimilarity(outputEmbed, expectedEmbed)
+
+		scores[i] = s.rescale(similarities[i])
 	}
 
-	result.Score = normalizedScore
-	result.Metadata["cosine_similarity"] = similarity
+	score, index := s.opts.Aggregation.Aggregate(scores)
+
+	result.Score = score
+	result.Metadata["cosine_similarity"] = similarities[index]
+	result.Metadata["rescaled_similarity"] = scores[index]
+	if s.opts.Calibration != nil {
+		result.Metadata["calibration"] = Calibration{Min: s.opts.Calibration.Min, Max: s.opts.Calibration.max()}
+	}
 	result.Metadata["embedding_dim"] = len(outputEmbed)
+	result.Metadata["reference_index"] = index
+	if len(references) > 1 {
+		result.Metadata["reference_scores"] = scores
+	}
 
 	return result
 }
 
+// rescale maps a cosine similarity into [0, 1] using the configured calibration
+func (s *embeddingSimilarityScorer) rescale(similarity float64) float64 {
+	if s.opts.Calibration != nil {
+		return s.opts.Calibration.Rescale(similarity)
+	}
+	// Normalize from [-1, 1] to [0, 1]
+	// In practice, embeddings are usually positive, so similarity is typically in [0, 1]
+	// But we handle the full range for robustness
+	return clamp((similarity + 1.0) / 2.0)
+}
+
 // cosineSimilarity computes the cosine similarity between two vectors
 // Returns a value between -1 and 1, where 1 means identical direction
 func cosineSimilarity(a, b []float64) float64 {
@@ -213,3 +213,42 @@ func TestCosineSimilarity(t *testing.T) {
 		})
 	}
 }
+
+func TestEmbeddingSimilarity_MultipleReferences(t *testing.T) {
+	ctx := context.Background()
+
+	embedder := &mockEmbedder{embeddings: map[string][]float64{
+		"Reset my password":   {1.0, 0.0, 0.0},
+		"How do I bake bread": {0.0, 1.0, 0.0},
+		"I forgot my login":   {0.9, 0.1, 0.0},
+	}}
+	in := api.ScoreInputs{
+		Output:               "Reset my password",
+		Expected:             "How do I bake bread",
+		ExpectedAlternatives: []string{"I forgot my login"},
+	}
+
+	best := EmbeddingSimilarity(embedder, EmbeddingSimilarityOptions{}).Score(ctx, in)
+	if best.Error != nil {
+		t.Fatalf("EmbeddingSimilarity.Score() unexpected error = %v", best.Error)
+	}
+	if best.Metadata["reference_index"] != 1 {
+		t.Errorf("EmbeddingSimilarity.Score() reference_index = %v, want 1", best.Metadata["reference_index"])
+	}
+	if best.Score < 0.95 {
+		t.Errorf("EmbeddingSimilarity.Score() score = %v, want >= 0.95", best.Score)
+	}
+
+	worst := EmbeddingSimilarity(embedder, EmbeddingSimilarityOptions{Aggregation: api.AggregateWorst}).Score(ctx, in)
+	if worst.Metadata["reference_index"] != 0 {
+		t.Errorf("EmbeddingSimilarity.Score() reference_index = %v, want 0", worst.Metadata["reference_index"])
+	}
+	if math.Abs(worst.Score-0.5) > 0.001 {
+		t.Errorf("EmbeddingSimilarity.Score() score = %v, want 0.5", worst.Score)
+	}
+
+	mean := EmbeddingSimilarity(embedder, EmbeddingSimilarityOptions{Aggregation: api.AggregateMean}).Score(ctx, in)
+	if math.Abs(mean.Score-(best.Score+worst.Score)/2) > 0.001 {
+		t.Errorf("EmbeddingSimilarity.Score() score = %v, want %v", mean.Score, (best.Score+worst.Score)/2)
+	}
+}
@@ -5,4 +5,9 @@ import "github.com/datar-psa/goeval/api"
 var (
 	ErrNoExpectedValue     = api.ErrNoExpectedValue
 	ErrLLMGenerationFailed = api.ErrLLMGenerationFailed
+	ErrNoConversation      = api.ErrNoConversation
+	ErrNoTrajectory        = api.ErrNoTrajectory
+
+	ErrMultimodalNotSupported = api.ErrMultimodalNotSupported
+	ErrBudgetExceeded         = api.ErrBudgetExceeded
 )
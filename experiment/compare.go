@@ -0,0 +1,305 @@
+package experiment
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/datar-psa/goeval/stats"
+)
+
+// Default comparison settings
+const (
+	DefaultAlpha = 0.05
+	DefaultTopN  = 5
+)
+
+// epsilon absorbs floating point noise when comparing deltas to thresholds
+const epsilon = 1e-9
+
+// CompareOptions configures Compare
+type CompareOptions struct {
+	// MaxRegression is the largest allowed drop of a scorer's mean score (default: 0, any drop fails)
+	MaxRegression float64
+	// ScorerMaxRegression overrides MaxRegression for individual scorers; names must match a scorer in the runs
+	ScorerMaxRegression map[string]float64
+	// AllowMissing lets the comparison pass when rows or scorers are present in only one run
+	AllowMissing bool
+	// RequireSignificance only fails a scorer when its drop is also significant under the paired bootstrap
+	RequireSignificance bool
+	// Alpha is the significance level of the paired tests (default: 0.05)
+	Alpha float64
+	// MinRowDelta is the smallest change of a row's score counted as a regression or improvement (default: any change)
+	MinRowDelta float64
+	// TopN is the number of regressed and improved rows listed per scorer (default: 5)
+	TopN int
+	// Bootstrap configures the paired bootstrap; its Confidence also sets the interval of the mean delta
+	Bootstrap stats.BootstrapOptions
+}
+
+// Comparison is the outcome of comparing a candidate run against a baseline run
+type Comparison struct {
+	Scorers []ScorerComparison `json:"scorers"`
+	// Rows is the number of row IDs present in both runs
+	Rows int `json:"rows"`
+	// MissingInCandidate and MissingInBaseline list row IDs present in only one run
+	MissingInCandidate []string `json:"missing_in_candidate,omitempty"`
+	MissingInBaseline  []string `json:"missing_in_baseline,omitempty"`
+	// UnmatchedScorers lists scorers present in only one run
+	UnmatchedScorers []string `json:"unmatched_scorers,omitempty"`
+	// Passed is true when every scorer passes and, unless AllowMissing is set, both runs cover the same rows and scorers
+	Passed bool `json:"passed"`
+}
+
+// ScorerComparison compares one scorer across the rows both runs have scored
+type ScorerComparison struct {
+	Scorer          string                      `json:"scorer"`
+	Rows            int                         `json:"rows"`
+	BaselineMean    float64                     `json:"baseline_mean"`
+	CandidateMean   float64                     `json:"candidate_mean"`
+	MeanDelta       float64                     `json:"mean_delta"`
+	BaselineErrors  int                         `json:"baseline_errors"`
+	CandidateErrors int                         `json:"candidate_errors"`
+	Bootstrap       stats.PairedBootstrapResult `json:"bootstrap"`
+	Wilcoxon        stats.WilcoxonResult        `json:"wilcoxon"`
+	// Significant is true when the paired bootstrap p-value is below Alpha
+	Significant     bool       `json:"significant"`
+	Regressed       int        `json:"regressed"`
+	Improved        int        `json:"improved"`
+	Unchanged       int        `json:"unchanged"`
+	TopRegressions  []RowDelta `json:"top_regressions,omitempty"`
+	TopImprovements []RowDelta `json:"top_improvements,omitempty"`
+	MaxRegression   float64    `json:"max_regression"`
+	Passed          bool       `json:"passed"`
+}
+
+// RowDelta is the change of one row's score between runs
+type RowDelta struct {
+	ID        string  `json:"id"`
+	Baseline  float64 `json:"baseline"`
+	Candidate float64 `json:"candidate"`
+	Delta     float64 `json:"delta"`
+}
+
+// Compare pairs the rows of two runs by ID and compares every scorer present in both
+// Errored scores count as 0, as in the run summary. A scorer passes when its mean drops by no more
+// than its allowed regression; the comparison passes when every scorer passes and no rows or scorers
+// are missing from either run (see AllowMissing)
+func Compare(baseline, candidate []Result, opts CompareOptions) (Comparison, error) {
+	if opts.Alpha <= 0 || opts.Alpha >= 1 {
+		opts.Alpha = DefaultAlpha
+	}
+	if opts.TopN <= 0 {
+		opts.TopN = DefaultTopN
+	}
+	if opts.MaxRegression < 0 {
+		return Comparison{}, fmt.Errorf("max regression must not be negative, got %v", opts.MaxRegression)
+	}
+
+	candidateByID := make(map[string]Result, len(candidate))
+	for _, result := range candidate {
+		candidateByID[result.ID] = result
+	}
+
+	var comparison Comparison
+	var pairs [][2]Result
+	baselineIDs := make(map[string]bool, len(baseline))
+	for _, result := range baseline {
+		baselineIDs[result.ID] = true
+		if other, ok := candidateByID[result.ID]; ok {
+			pairs = append(pairs, [2]Result{result, other})
+		} else {
+			comparison.MissingInCandidate = append(comparison.MissingInCandidate, result.ID)
+		}
+	}
+	for _, result := range candidate {
+		if !baselineIDs[result.ID] {
+			comparison.MissingInBaseline = append(comparison.MissingInBaseline, result.ID)
+		}
+	}
+	if len(pairs) == 0 {
+		return Comparison{}, fmt.Errorf("the runs have no row IDs in common")
+	}
+	comparison.Rows = len(pairs)
+
+	baselineScorers, candidateScorers := scorerNames(baseline), scorerNames(candidate)
+	for _, name := range baselineScorers {
+		if !slices.Contains(candidateScorers, name) {
+			comparison.UnmatchedScorers = append(comparison.UnmatchedScorers, name)
+		}
+	}
+	for _, name := range candidateScorers {
+		if !slices.Contains(baselineScorers, name) {
+			comparison.UnmatchedScorers = append(comparison.UnmatchedScorers, name)
+		}
+	}
+
+	for name := range opts.ScorerMaxRegression {
+		if !slices.Contains(baselineScorers, name) && !slices.Contains(candidateScorers, name) {
+			return Comparison{}, fmt.Errorf("max regression set for unknown scorer %q (scorers: %s)", name, strings.Join(baselineScorers, ", "))
+		}
+	}
+
+	comparison.Passed = opts.AllowMissing ||
+		len(comparison.MissingInCandidate)+len(comparison.MissingInBaseline)+len(comparison.UnmatchedScorers) == 0
+	for _, name := range baselineScorers {
+		if !slices.Contains(candidateScorers, name) {
+			continue
+		}
+		scorer, err := compareScorer(name, pairs, opts)
+		if err != nil {
+			return Comparison{}, err
+		}
+		comparison.Passed = comparison.Passed && scorer.Passed
+		comparison.Scorers = append(comparison.Scorers, scorer)
+	}
+	return comparison, nil
+}
+
+func compareScorer(name string, pairs [][2]Result, opts CompareOptions) (ScorerComparison, error) {
+	comparison := ScorerComparison{Scorer: name, MaxRegression: opts.MaxRegression}
+	if limit, ok := opts.ScorerMaxRegression[name]; ok {
+		if limit < 0 {
+			return ScorerComparison{}, fmt.Errorf("max regression of %s must not be negative, got %v", name, limit)
+		}
+		comparison.MaxRegression = limit
+	}
+
+	var baseline, candidate []float64
+	var deltas []RowDelta
+	for _, pair := range pairs {
+		before, ok := findScore(pair[0], name)
+		if !ok {
+			continue
+		}
+		after, ok := findScore(pair[1], name)
+		if !ok {
+			continue
+		}
+		if before.Error != "" {
+			comparison.BaselineErrors++
+		}
+		if after.Error != "" {
+			comparison.CandidateErrors++
+		}
+		baseline = append(baseline, before.Score)
+		candidate = append(candidate, after.Score)
+		deltas = append(deltas, RowDelta{ID: pair[0].ID, Baseline: before.Score, Candidate: after.Score, Delta: after.Score - before.Score})
+	}
+	comparison.Rows = len(deltas)
+	if len(deltas) == 0 {
+		// The scorer never ran on a shared row, so there is nothing to regress
+		comparison.Passed = true
+		return comparison, nil
+	}
+
+	for i := range deltas {
+		comparison.BaselineMean += baseline[i]
+		comparison.CandidateMean += candidate[i]
+	}
+	comparison.BaselineMean /= float64(len(deltas))
+	comparison.CandidateMean /= float64(len(deltas))
+	comparison.MeanDelta = comparison.CandidateMean - comparison.BaselineMean
+
+	var err error
+	if comparison.Bootstrap, err = stats.PairedBootstrap(baseline, candidate, opts.Bootstrap); err != nil {
+		return ScorerComparison{}, fmt.Errorf("%s: %w", name, err)
+	}
+	if comparison.Wilcoxon, err = stats.WilcoxonSignedRank(baseline, candidate); err != nil {
+		return ScorerComparison{}, fmt.Errorf("%s: %w", name, err)
+	}
+	comparison.Significant = comparison.Bootstrap.PValue < opts.Alpha
+
+	var regressions, improvements []RowDelta
+	for _, delta := range deltas {
+		switch {
+		case delta.Delta < 0 && -delta.Delta >= opts.MinRowDelta:
+			regressions = append(regressions, delta)
+		case delta.Delta > 0 && delta.Delta >= opts.MinRowDelta:
+			improvements = append(improvements, delta)
+		default:
+			comparison.Unchanged++
+		}
+	}
+	comparison.Regressed, comparison.Improved = len(regressions), len(improvements)
+	// Largest changes first, ties in row order
+	slices.SortStableFunc(regressions, func(a, b RowDelta) int { return cmp.Compare(a.Delta, b.Delta) })
+	slices.SortStableFunc(improvements, func(a, b RowDelta) int { return cmp.Compare(b.Delta, a.Delta) })
+	comparison.TopRegressions = regressions[:min(opts.TopN, len(regressions))]
+	comparison.TopImprovements = improvements[:min(opts.TopN, len(improvements))]
+
+	dropped := -comparison.MeanDelta > comparison.MaxRegression+epsilon
+	comparison.Passed = !dropped || (opts.RequireSignificance && !comparison.Significant)
+	return comparison, nil
+}
+
+// scorerNames returns scorer names in order of first appearance
+func scorerNames(results []Result) []string {
+	var names []string
+	for _, result := range results {
+		for _, score := range result.Scores {
+			if !slices.Contains(names, score.Scorer) {
+				names = append(names, score.Scorer)
+			}
+		}
+	}
+	return names
+}
+
+func findScore(result Result, scorer string) (ScoreResult, bool) {
+	for _, score := range result.Scores {
+		if score.Scorer == scorer {
+			return score, true
+		}
+	}
+	return ScoreResult{}, false
+}
+
+// WriteTable prints one line per scorer followed by the top regressed and improved rows
+func (c Comparison) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "SCORER\tROWS\tBASELINE\tCANDIDATE\tDELTA\tCI\tP (BOOTSTRAP)\tP (WILCOXON)\t-/+/=\tALLOWED\tSTATUS\n")
+	for _, s := range c.Scorers {
+		status := "ok"
+		if !s.Passed {
+			status = "FAIL"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%.3f\t%.3f\t%+.3f\t[%+.3f, %+.3f]\t%.4f\t%.4f\t%d/%d/%d\t%.3f\t%s\n",
+			s.Scorer, s.Rows, s.BaselineMean, s.CandidateMean, s.MeanDelta, s.Bootstrap.Low, s.Bootstrap.High,
+			s.Bootstrap.PValue, s.Wilcoxon.PValue, s.Regressed, s.Improved, s.Unchanged, s.MaxRegression, status)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, s := range c.Scorers {
+		writeRowDeltas(tw, s.Scorer+": top regressions", s.TopRegressions)
+		writeRowDeltas(tw, s.Scorer+": top improvements", s.TopImprovements)
+	}
+	if len(c.MissingInCandidate) > 0 {
+		fmt.Fprintf(tw, "\n%d rows missing in candidate: %v\n", len(c.MissingInCandidate), c.MissingInCandidate)
+	}
+	if len(c.MissingInBaseline) > 0 {
+		fmt.Fprintf(tw, "\n%d rows missing in baseline: %v\n", len(c.MissingInBaseline), c.MissingInBaseline)
+	}
+	if len(c.UnmatchedScorers) > 0 {
+		fmt.Fprintf(tw, "\nscorers in only one run: %v\n", c.UnmatchedScorers)
+	}
+	if !c.Passed && !slices.ContainsFunc(c.Scorers, func(s ScorerComparison) bool { return !s.Passed }) {
+		fmt.Fprintf(tw, "\nFAIL: the runs cover different rows or scorers\n")
+	}
+	return tw.Flush()
+}
+
+func writeRowDeltas(w io.Writer, title string, deltas []RowDelta) {
+	if len(deltas) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s\n", title)
+	for _, d := range deltas {
+		fmt.Fprintf(w, "  %s\t%.3f -> %.3f\t%+.3f\n", d.ID, d.Baseline, d.Candidate, d.Delta)
+	}
+}
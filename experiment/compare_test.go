@@ -0,0 +1,300 @@
+package experiment
+
+import (
+	"bytes"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func results(scorer string, scores map[string]float64, ids ...string) []Result {
+	out := make([]Result, len(ids))
+	for i, id := range ids {
+		out[i] = Result{ID: id, Scores: []ScoreResult{{Scorer: scorer, Score: scores[id]}}}
+	}
+	return out
+}
+
+func TestCompare(t *testing.T) {
+	ids := []string{"a", "b", "c", "d"}
+	baseline := results("exact", map[string]float64{"a": 1, "b": 1, "c": 0, "d": 0.5}, ids...)
+
+	tests := []struct {
+		name            string
+		candidate       map[string]float64
+		opts            CompareOptions
+		wantDelta       float64
+		wantPassed      bool
+		wantRegressions []string
+		wantImprovement []string
+	}{
+		{
+			name:            "regression fails",
+			candidate:       map[string]float64{"a": 0, "b": 1, "c": 0, "d": 0.25},
+			wantDelta:       -0.3125,
+			wantPassed:      false,
+			wantRegressions: []string{"a", "d"},
+		},
+		{
+			name:            "regression within allowance",
+			candidate:       map[string]float64{"a": 0, "b": 1, "c": 0, "d": 0.25},
+			opts:            CompareOptions{MaxRegression: 0.4},
+			wantDelta:       -0.3125,
+			wantPassed:      true,
+			wantRegressions: []string{"a", "d"},
+		},
+		{
+			name:            "scorer allowance overrides default",
+			candidate:       map[string]float64{"a": 0, "b": 1, "c": 0, "d": 0.25},
+			opts:            CompareOptions{MaxRegression: 0.4, ScorerMaxRegression: map[string]float64{"exact": 0.1}},
+			wantDelta:       -0.3125,
+			wantPassed:      false,
+			wantRegressions: []string{"a", "d"},
+		},
+		{
+			name:            "insignificant regression passes when significance is required",
+			candidate:       map[string]float64{"a": 0, "b": 1, "c": 1, "d": 0.25},
+			opts:            CompareOptions{RequireSignificance: true},
+			wantDelta:       -0.0625,
+			wantPassed:      true,
+			wantRegressions: []string{"a", "d"},
+			wantImprovement: []string{"c"},
+		},
+		{
+			name:            "small row changes are ignored",
+			candidate:       map[string]float64{"a": 1, "b": 1, "c": 1, "d": 0.45},
+			opts:            CompareOptions{MinRowDelta: 0.1},
+			wantDelta:       0.2375,
+			wantPassed:      true,
+			wantImprovement: []string{"c"},
+		},
+		{
+			name:            "top N",
+			candidate:       map[string]float64{"a": 0, "b": 0.9, "c": 0, "d": 0},
+			opts:            CompareOptions{TopN: 2},
+			wantDelta:       -0.4,
+			wantPassed:      false,
+			wantRegressions: []string{"a", "d"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compare(baseline, results("exact", tt.candidate, ids...), tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Scorers) != 1 {
+				t.Fatalf("got %d scorers, want 1", len(got.Scorers))
+			}
+			scorer := got.Scorers[0]
+			if math.Abs(scorer.MeanDelta-tt.wantDelta) > 1e-9 {
+				t.Errorf("MeanDelta = %v, want %v", scorer.MeanDelta, tt.wantDelta)
+			}
+			if got.Passed != tt.wantPassed || scorer.Passed != tt.wantPassed {
+				t.Errorf("Passed = %v (scorer %v), want %v", got.Passed, scorer.Passed, tt.wantPassed)
+			}
+			if ids := rowIDs(scorer.TopRegressions); !slices.Equal(ids, tt.wantRegressions) {
+				t.Errorf("TopRegressions = %v, want %v", ids, tt.wantRegressions)
+			}
+			if ids := rowIDs(scorer.TopImprovements); !slices.Equal(ids, tt.wantImprovement) {
+				t.Errorf("TopImprovements = %v, want %v", ids, tt.wantImprovement)
+			}
+			if scorer.Regressed+scorer.Improved+scorer.Unchanged != scorer.Rows {
+				t.Errorf("regressed %d + improved %d + unchanged %d != rows %d", scorer.Regressed, scorer.Improved, scorer.Unchanged, scorer.Rows)
+			}
+		})
+	}
+}
+
+func rowIDs(deltas []RowDelta) []string {
+	var ids []string
+	for _, d := range deltas {
+		ids = append(ids, d.ID)
+	}
+	return ids
+}
+
+func TestCompare_Significance(t *testing.T) {
+	var ids []string
+	before, after := make(map[string]float64), make(map[string]float64)
+	for i := range 30 {
+		id := string(rune('A' + i))
+		ids = append(ids, id)
+		before[id] = 0.8
+		after[id] = 0.6 + float64(i%3)*0.05
+	}
+
+	got, err := Compare(results("judge", before, ids...), results("judge", after, ids...), CompareOptions{MaxRegression: 0.05})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scorer := got.Scorers[0]
+	if !scorer.Significant || scorer.Bootstrap.PValue >= 0.05 || scorer.Wilcoxon.PValue >= 0.05 {
+		t.Errorf("expected a significant drop, got bootstrap p=%v wilcoxon p=%v", scorer.Bootstrap.PValue, scorer.Wilcoxon.PValue)
+	}
+	if scorer.Bootstrap.High >= 0 {
+		t.Errorf("interval [%v, %v] should exclude 0", scorer.Bootstrap.Low, scorer.Bootstrap.High)
+	}
+	if got.Passed {
+		t.Error("expected the comparison to fail")
+	}
+}
+
+func TestCompare_MismatchedRuns(t *testing.T) {
+	baseline := []Result{
+		{ID: "a", Scores: []ScoreResult{{Scorer: "exact", Score: 1}, {Scorer: "old", Score: 1}}},
+		{ID: "b", Scores: []ScoreResult{{Scorer: "exact", Score: 1}}},
+	}
+	candidate := []Result{
+		{ID: "a", Scores: []ScoreResult{{Scorer: "exact", Score: 0, Error: "timeout"}, {Scorer: "new", Score: 1}}},
+		{ID: "c", Scores: []ScoreResult{{Scorer: "exact", Score: 1}}},
+	}
+
+	got, err := Compare(baseline, candidate, CompareOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Rows != 1 || !slices.Equal(got.MissingInCandidate, []string{"b"}) || !slices.Equal(got.MissingInBaseline, []string{"c"}) {
+		t.Errorf("rows = %d, missing in candidate %v, missing in baseline %v", got.Rows, got.MissingInCandidate, got.MissingInBaseline)
+	}
+	if !slices.Equal(got.UnmatchedScorers, []string{"old", "new"}) {
+		t.Errorf("UnmatchedScorers = %v", got.UnmatchedScorers)
+	}
+	if len(got.Scorers) != 1 || got.Scorers[0].CandidateErrors != 1 || got.Scorers[0].MeanDelta != -1 {
+		t.Errorf("Scorers = %+v", got.Scorers)
+	}
+
+	var table bytes.Buffer
+	if err := got.WriteTable(&table); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"exact", "-1.000", "FAIL", "exact: top regressions", "missing in candidate: [b]", "scorers in only one run: [old new]"} {
+		if !strings.Contains(table.String(), want) {
+			t.Errorf("table missing %q:\n%s", want, table.String())
+		}
+	}
+
+	if _, err := Compare(baseline, []Result{{ID: "z"}}, CompareOptions{}); err == nil {
+		t.Error("expected error for runs without common rows")
+	}
+	if _, err := Compare(baseline, candidate, CompareOptions{MaxRegression: -1}); err == nil {
+		t.Error("expected error for a negative max regression")
+	}
+}
+
+func TestCompare_Missing(t *testing.T) {
+	baseline := results("exact", map[string]float64{"a": 1, "b": 1}, "a", "b")
+	candidate := results("exact", map[string]float64{"a": 1}, "a")
+	withExtraScorer := []Result{{ID: "a", Scores: []ScoreResult{{Scorer: "exact", Score: 1}, {Scorer: "new", Score: 1}}}, {ID: "b", Scores: []ScoreResult{{Scorer: "exact", Score: 1}}}}
+
+	tests := []struct {
+		name      string
+		candidate []Result
+		opts      CompareOptions
+		want      bool
+	}{
+		{name: "missing rows fail", candidate: candidate, want: false},
+		{name: "missing rows allowed", candidate: candidate, opts: CompareOptions{AllowMissing: true}, want: true},
+		{name: "unmatched scorer fails", candidate: withExtraScorer, want: false},
+		{name: "unmatched scorer allowed", candidate: withExtraScorer, opts: CompareOptions{AllowMissing: true}, want: true},
+		{name: "same rows and scorers", candidate: baseline, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compare(baseline, tt.candidate, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Passed != tt.want {
+				t.Errorf("Passed = %v, want %v", got.Passed, tt.want)
+			}
+			if !got.Scorers[0].Passed {
+				t.Error("scorer should pass; only coverage differs")
+			}
+		})
+	}
+
+	got, _ := Compare(baseline, candidate, CompareOptions{})
+	var table bytes.Buffer
+	if err := got.WriteTable(&table); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(table.String(), "FAIL: the runs cover different rows or scorers") {
+		t.Errorf("table does not explain the failure:\n%s", table.String())
+	}
+}
+
+func TestCompare_UnknownScorerLimit(t *testing.T) {
+	run := results("exact", map[string]float64{"a": 1}, "a")
+	_, err := Compare(run, run, CompareOptions{ScorerMaxRegression: map[string]float64{"exct": 0.1}})
+	if err == nil || !strings.Contains(err.Error(), `unknown scorer "exct"`) {
+		t.Errorf("error = %v, want unknown scorer error", err)
+	}
+}
+
+func TestDecodeResults(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantIDs []string
+		wantErr string
+	}{
+		{
+			name:    "JSONL",
+			input:   `{"id": "a", "scores": [{"scorer": "exact", "score": 1}]}` + "\n" + `{"id": "b", "scores": []}` + "\n",
+			wantIDs: []string{"a", "b"},
+		},
+		{
+			name:    "usage line",
+			input:   `{"id": "a", "scores": []}` + "\n" + `{"usage": {"total": {"calls": 1}}}` + "\n",
+			wantIDs: []string{"a"},
+		},
+		{name: "duplicate ID", input: `{"id": "a"}` + "\n" + `{"id": "a"}`, wantErr: "duplicate"},
+		{name: "missing ID", input: `{"scores": []}`, wantErr: "no id"},
+		{name: "invalid JSON", input: `{"id": `, wantErr: "invalid result 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeResults(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			for _, r := range got {
+				ids = append(ids, r.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("IDs = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestScores(t *testing.T) {
+	input := []Result{
+		{ID: "a", Tags: map[string]string{"lang": "en"}, Scores: []ScoreResult{{Scorer: "exact", Score: 1}}},
+		{ID: "b", Scores: []ScoreResult{{Scorer: "other", Score: 1}}},
+		{ID: "c", Scores: []ScoreResult{{Scorer: "exact", Error: "timeout"}}},
+	}
+
+	got := Scores(input, "exact")
+	if len(got) != 2 {
+		t.Fatalf("got %d scores, want 2", len(got))
+	}
+	if got[0].Score.Score != 1 || got[0].Tags["lang"] != "en" || got[0].Score.Error != nil {
+		t.Errorf("first score = %+v", got[0])
+	}
+	if got[1].Score.Error == nil || got[1].Score.Error.Error() != "timeout" {
+		t.Errorf("second score error = %v, want timeout", got[1].Score.Error)
+	}
+}
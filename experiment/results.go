@@ -0,0 +1,128 @@
+// Package experiment reads eval run results and compares runs against each other
+package experiment
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/datar-psa/goeval/api"
+	"github.com/datar-psa/goeval/cost"
+	"github.com/datar-psa/goeval/stats"
+)
+
+// Result is one row of a JSONL results file, as written by goeval run
+type Result struct {
+	ID     string            `json:"id"`
+	Tags   map[string]string `json:"tags,omitempty"`
+	Scores []ScoreResult     `json:"scores"`
+}
+
+// ScoreResult is the outcome of one scorer on one row
+type ScoreResult struct {
+	Scorer   string         `json:"scorer"`
+	Score    float64        `json:"score"`
+	Error    string         `json:"error,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// UsageLine is the last line of a results file written by goeval run, with the run's token usage
+// and estimated cost per scorer and per model
+type UsageLine struct {
+	Usage cost.Report `json:"usage"`
+}
+
+// ReadResults reads a JSONL results file
+func ReadResults(path string) ([]Result, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open results file: %w", err)
+	}
+	defer file.Close()
+
+	results, err := DecodeResults(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return results, nil
+}
+
+// DecodeResults reads JSONL results, one row per line
+// Row IDs must be unique. The usage line written by goeval run is skipped
+func DecodeResults(r io.Reader) ([]Result, error) {
+	var results []Result
+	seen := make(map[string]bool)
+	decoder := json.NewDecoder(r)
+	for {
+		var line struct {
+			Result
+			Usage *cost.Report `json:"usage"`
+		}
+		if err := decoder.Decode(&line); err == io.EOF {
+			return results, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid result %d: %w", len(results)+1, err)
+		}
+		result := line.Result
+		if result.ID == "" && line.Usage != nil {
+			continue
+		}
+		if result.ID == "" {
+			return nil, fmt.Errorf("result %d has no id", len(results)+1)
+		}
+		if seen[result.ID] {
+			return nil, fmt.Errorf("duplicate result id %q", result.ID)
+		}
+		seen[result.ID] = true
+		results = append(results, result)
+	}
+}
+
+// WriteResults writes results to a JSONL file
+func WriteResults(path string, results []Result) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create results file: %w", err)
+	}
+	if err := EncodeResults(file, results); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// EncodeResults writes one JSON line per row
+func EncodeResults(w io.Writer, results []Result) error {
+	buffered := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffered)
+	for _, result := range results {
+		if err := encoder.Encode(result); err != nil {
+			return fmt.Errorf("failed to write results: %w", err)
+		}
+	}
+	if err := buffered.Flush(); err != nil {
+		return fmt.Errorf("failed to write results: %w", err)
+	}
+	return nil
+}
+
+// Scores returns one scorer's scores with the tags of their rows, for stats.Summarize and stats.SummarizeBy
+// Rows the scorer did not run on are skipped
+func Scores(results []Result, scorer string) []stats.TaggedScore {
+	var scores []stats.TaggedScore
+	for _, result := range results {
+		score, ok := findScore(result, scorer)
+		if !ok {
+			continue
+		}
+		tagged := stats.TaggedScore{Score: api.Score{Name: scorer, Score: score.Score, Metadata: score.Metadata}, Tags: result.Tags}
+		if score.Error != "" {
+			tagged.Score.Error = errors.New(score.Error)
+		}
+		scores = append(scores, tagged)
+	}
+	return scores
+}
@@ -0,0 +1,148 @@
+package fake
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// Embedder is a scriptable api.Embedder and api.BatchEmbedder
+type Embedder struct {
+	script script[[]float64]
+
+	mu    sync.Mutex
+	calls []EmbedderCall
+}
+
+// EmbedderCall is a recorded call to an Embedder
+type EmbedderCall struct {
+	Text     string
+	Response []float64
+	Err      error
+}
+
+// EmbedderOptions configures an Embedder
+type EmbedderOptions struct {
+	latency time.Duration
+}
+
+// WithEmbedderLatency delays every response by latency, in addition to per-rule latency
+func WithEmbedderLatency(latency time.Duration) func(*EmbedderOptions) {
+	return func(opts *EmbedderOptions) {
+		opts.latency = latency
+	}
+}
+
+// NewEmbedder creates an Embedder without rules; calls fail with ErrNoResponse until rules are added with On
+func NewEmbedder(opts ...func(*EmbedderOptions)) *Embedder {
+	options := EmbedderOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	e := &Embedder{}
+	e.script.latency = options.latency
+	return e
+}
+
+// On adds a rule for texts accepted by match, e.g. e.On(fake.Exact("cat")).Return([]float64{1, 0})
+func (e *Embedder) On(match Matcher) *Rule[[]float64] {
+	return e.script.on(match)
+}
+
+// Calls returns the calls made so far; a batch records one call per text
+func (e *Embedder) Calls() []EmbedderCall {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]EmbedderCall(nil), e.calls...)
+}
+
+// Embed implements Embedder.Embed
+func (e *Embedder) Embed(ctx context.Context, text string) ([]float64, error) {
+	response, err := e.script.respond(ctx, text)
+
+	e.mu.Lock()
+	e.calls = append(e.calls, EmbedderCall{Text: text, Response: response, Err: err})
+	e.mu.Unlock()
+
+	return response, err
+}
+
+// EmbedBatch implements BatchEmbedder.EmbedBatch, answering each text as Embed does
+func (e *Embedder) EmbedBatch(ctx context.Context, texts []string) ([][]float64, error) {
+	embeddings := make([][]float64, len(texts))
+	for i, text := range texts {
+		embedding, err := e.Embed(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		embeddings[i] = embedding
+	}
+	return embeddings, nil
+}
+
+// EmbedderRecorder wraps a real embedder and records its vectors for ReplayEmbedder
+type EmbedderRecorder struct {
+	embedder api.Embedder
+	recorder recorder[[]float64]
+}
+
+// RecordEmbedder returns a recorder that passes calls through to embedder
+func RecordEmbedder(embedder api.Embedder) *EmbedderRecorder {
+	return &EmbedderRecorder{embedder: embedder}
+}
+
+// Embed implements Embedder.Embed
+func (r *EmbedderRecorder) Embed(ctx context.Context, text string) ([]float64, error) {
+	response, err := r.embedder.Embed(ctx, text)
+	r.recorder.record(text, response, err)
+	return response, err
+}
+
+// EmbedBatch implements BatchEmbedder.EmbedBatch
+// Batches are passed through when the wrapped embedder implements api.BatchEmbedder, and every text is recorded
+func (r *EmbedderRecorder) EmbedBatch(ctx context.Context, texts []string) ([][]float64, error) {
+	batch, ok := r.embedder.(api.BatchEmbedder)
+	if !ok {
+		embeddings := make([][]float64, len(texts))
+		for i, text := range texts {
+			embedding, err := r.Embed(ctx, text)
+			if err != nil {
+				return nil, err
+			}
+			embeddings[i] = embedding
+		}
+		return embeddings, nil
+	}
+
+	embeddings, err := batch.EmbedBatch(ctx, texts)
+	for i, text := range texts {
+		if err != nil {
+			r.recorder.record(text, nil, err)
+		} else {
+			r.recorder.record(text, embeddings[i], nil)
+		}
+	}
+	return embeddings, err
+}
+
+// Save writes the recorded calls to a JSON fixture at path
+func (r *EmbedderRecorder) Save(path string) error {
+	return r.recorder.save(path)
+}
+
+// ReplayEmbedder creates an Embedder that answers the texts recorded in the fixture at path
+func ReplayEmbedder(path string, opts ...func(*EmbedderOptions)) (*Embedder, error) {
+	e := NewEmbedder(opts...)
+	if err := replay(path, &e.script); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Verify that Embedder and EmbedderRecorder implement goeval.Embedder and goeval.BatchEmbedder
+var (
+	_ api.BatchEmbedder = (*Embedder)(nil)
+	_ api.BatchEmbedder = (*EmbedderRecorder)(nil)
+)
@@ -0,0 +1,206 @@
+// Package fake provides scriptable fakes of LLMGenerator, Embedder and ModerationProvider for tests,
+// and recorders that capture real provider responses into JSON fixtures for offline replay
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoResponse is returned when no rule matches an input
+var ErrNoResponse = errors.New("fake: no response scripted")
+
+// Matcher decides whether a rule applies to an input (a prompt, a text to embed or content to moderate)
+type Matcher func(input string) bool
+
+// Any matches every input
+func Any() Matcher {
+	return func(string) bool { return true }
+}
+
+// Exact matches inputs equal to s
+func Exact(s string) Matcher {
+	return func(input string) bool { return input == s }
+}
+
+// Contains matches inputs containing substr
+func Contains(substr string) Matcher {
+	return func(input string) bool { return strings.Contains(input, substr) }
+}
+
+// Regexp matches inputs matching pattern; it panics if pattern does not compile
+func Regexp(pattern string) Matcher {
+	re := regexp.MustCompile(pattern)
+	return re.MatchString
+}
+
+// Rule is a scripted response for inputs accepted by its matcher
+type Rule[T any] struct {
+	match    Matcher
+	response T
+	err      error
+	latency  time.Duration
+	times    int
+	used     int
+}
+
+// Return sets the response returned by the rule
+func (r *Rule[T]) Return(response T) *Rule[T] {
+	r.response = response
+	return r
+}
+
+// ReturnError makes the rule fail with err
+func (r *Rule[T]) ReturnError(err error) *Rule[T] {
+	r.err = err
+	return r
+}
+
+// After delays the response by latency, or until the context is done
+func (r *Rule[T]) After(latency time.Duration) *Rule[T] {
+	r.latency = latency
+	return r
+}
+
+// Times limits the rule to the first n matching calls; later calls fall through to the next rules
+func (r *Rule[T]) Times(n int) *Rule[T] {
+	r.times = n
+	return r
+}
+
+// script holds rules in the order they were added; the first matching rule with uses left answers
+type script[T any] struct {
+	mu      sync.Mutex
+	rules   []*Rule[T]
+	latency time.Duration
+}
+
+func (s *script[T]) on(match Matcher) *Rule[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rule := &Rule[T]{match: match}
+	s.rules = append(s.rules, rule)
+	return rule
+}
+
+// respond finds the rule for input and waits for its latency
+func (s *script[T]) respond(ctx context.Context, input string) (T, error) {
+	var zero T
+
+	s.mu.Lock()
+	var rule *Rule[T]
+	for _, r := range s.rules {
+		if (r.times == 0 || r.used < r.times) && r.match(input) {
+			r.used++
+			rule = r
+			break
+		}
+	}
+	latency := s.latency
+	if rule != nil {
+		latency += rule.latency
+	}
+	s.mu.Unlock()
+
+	if err := wait(ctx, latency); err != nil {
+		return zero, err
+	}
+	if rule == nil {
+		return zero, fmt.Errorf("%w for %q", ErrNoResponse, truncate(input, 80))
+	}
+	if rule.err != nil {
+		return zero, rule.err
+	}
+	return rule.response, nil
+}
+
+// wait sleeps for d, returning early with the context error when ctx is done
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
+
+// entry is one recorded call in a fixture file
+type entry[T any] struct {
+	Input    string `json:"input"`
+	Response T      `json:"response,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// recorder collects entries of a wrapped provider
+type recorder[T any] struct {
+	mu      sync.Mutex
+	entries []entry[T]
+}
+
+func (r *recorder[T]) record(input string, response T, err error) {
+	e := entry[T]{Input: input, Response: response}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, e)
+}
+
+// save writes the recorded entries as indented JSON
+func (r *recorder[T]) save(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := json.MarshalIndent(r.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fixture: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// replay scripts an exact-match rule for every entry of the fixture at path
+// Entries for the same input are replayed in recorded order; the last one repeats
+func replay[T any](path string, s *script[T]) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var entries []entry[T]
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+
+	remaining := make(map[string]int, len(entries))
+	for _, e := range entries {
+		remaining[e.Input]++
+	}
+	for _, e := range entries {
+		rule := s.on(Exact(e.Input)).Return(e.Response)
+		if e.Error != "" {
+			rule.ReturnError(errors.New(e.Error))
+		}
+		if remaining[e.Input]--; remaining[e.Input] > 0 {
+			rule.Times(1)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/datar-psa/goeval/api"
+	"github.com/datar-psa/goeval/embedding"
+)
+
+func TestMatchers(t *testing.T) {
+	tests := []struct {
+		name    string
+		matcher Matcher
+		input   string
+		want    bool
+	}{
+		{"any", Any(), "anything", true},
+		{"exact", Exact("cat"), "cat", true},
+		{"exact mismatch", Exact("cat"), "cats", false},
+		{"contains", Contains("France"), "capital of France?", true},
+		{"contains mismatch", Contains("Spain"), "capital of France?", false},
+		{"regexp", Regexp(`^\d+$`), "42", true},
+		{"regexp mismatch", Regexp(`^\d+$`), "4x", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.matcher(tt.input); got != tt.want {
+				t.Errorf("matcher(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerator_Rules(t *testing.T) {
+	ctx := context.Background()
+	errUnavailable := errors.New("unavailable")
+
+	g := NewGenerator(WithUsage(api.Usage{Model: "fake", Calls: 1, PromptTokens: 10}))
+	g.On(Contains("flaky")).ReturnError(errUnavailable).Times(1)
+	g.On(Contains("France")).Return(map[string]any{"choice": "A"})
+	g.On(Any()).Return(map[string]any{"choice": "E"})
+
+	if got, _ := g.StructuredGenerate(ctx, "capital of France", nil); got["choice"] != "A" {
+		t.Errorf("France: choice = %v, want A", got["choice"])
+	}
+	if got, _ := g.StructuredGenerate(ctx, "other", nil); got["choice"] != "E" {
+		t.Errorf("fallback: choice = %v, want E", got["choice"])
+	}
+
+	// The injected error applies once, then later rules answer
+	if _, err := g.StructuredGenerate(ctx, "flaky", nil); !errors.Is(err, errUnavailable) {
+		t.Errorf("first flaky call: error = %v, want %v", err, errUnavailable)
+	}
+	if got, err := g.StructuredGenerate(ctx, "flaky", nil); err != nil || got["choice"] != "E" {
+		t.Errorf("second flaky call = %v, %v, want choice E", got, err)
+	}
+
+	result, err := g.StructuredGenerateContentWithUsage(ctx, []api.Part{api.TextPart("France"), api.ImagePart([]byte{1}, "image/png")}, map[string]any{"type": "object"})
+	if err != nil || result.Data["choice"] != "A" || result.Usage.PromptTokens != 10 {
+		t.Errorf("multimodal = %+v, %v, want choice A with usage", result, err)
+	}
+
+	calls := g.Calls()
+	if len(calls) != 5 {
+		t.Fatalf("calls = %d, want 5", len(calls))
+	}
+	if last := calls[4]; last.Prompt != "France" || len(last.Parts) != 2 || last.Schema["type"] != "object" {
+		t.Errorf("last call = %+v, want prompt, parts and schema recorded", last)
+	}
+	if !errors.Is(calls[2].Err, errUnavailable) {
+		t.Errorf("calls[2].Err = %v, want %v", calls[2].Err, errUnavailable)
+	}
+}
+
+func TestGenerator_NoResponse(t *testing.T) {
+	if _, err := NewGenerator().StructuredGenerate(context.Background(), "prompt", nil); !errors.Is(err, ErrNoResponse) {
+		t.Errorf("error = %v, want ErrNoResponse", err)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	e := NewEmbedder(WithEmbedderLatency(10 * time.Millisecond))
+	e.On(Any()).Return([]float64{1})
+
+	start := time.Now()
+	if _, err := e.Embed(context.Background(), "text"); err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("elapsed = %v, want at least 10ms", elapsed)
+	}
+
+	slow := NewModerationProvider()
+	slow.On(Any()).Return(&api.ModerationResult{}).After(time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := slow.Moderate(ctx, "content"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Moderate() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestEmbedder_WithScorer(t *testing.T) {
+	e := NewEmbedder()
+	e.On(Exact("reset my password")).Return([]float64{1, 0})
+	e.On(Exact("I can't log in")).Return([]float64{0.8, 0.6})
+
+	result := embedding.EmbeddingSimilarity(e, embedding.EmbeddingSimilarityOptions{}).Score(context.Background(), api.ScoreInputs{
+		Output:   "reset my password",
+		Expected: "I can't log in",
+	})
+	if result.Error != nil || result.Metadata["cosine_similarity"].(float64) < 0.79 {
+		t.Errorf("Score() = %+v, want cosine similarity 0.8", result)
+	}
+	if len(e.Calls()) != 2 {
+		t.Errorf("calls = %d, want 2", len(e.Calls()))
+	}
+}
+
+func TestEmbedder_EmbedBatch(t *testing.T) {
+	e := NewEmbedder()
+	e.On(Exact("a")).Return([]float64{1, 0})
+	e.On(Exact("b")).Return([]float64{0, 1})
+
+	got, err := e.EmbedBatch(context.Background(), []string{"a", "b"})
+	if err != nil || !reflect.DeepEqual(got, [][]float64{{1, 0}, {0, 1}}) {
+		t.Errorf("EmbedBatch() = %v, %v, want [[1 0] [0 1]]", got, err)
+	}
+	if len(e.Calls()) != 2 {
+		t.Errorf("calls = %d, want one per text", len(e.Calls()))
+	}
+	if _, err := e.EmbedBatch(context.Background(), []string{"a", "unknown"}); !errors.Is(err, ErrNoResponse) {
+		t.Errorf("EmbedBatch() error = %v, want ErrNoResponse", err)
+	}
+}
+
+// configuredGenerator is a Generator that also reports its settings
+type configuredGenerator struct{ *Generator }
+
+func (g configuredGenerator) GenerationConfig() map[string]any {
+	return map[string]any{"model": "m"}
+}
+
+func TestGeneratorRecorder_PassThrough(t *testing.T) {
+	ctx := context.Background()
+	usage := api.Usage{Model: "m", Calls: 1, PromptTokens: 10, OutputTokens: 2}
+	real := NewGenerator(WithUsage(usage))
+	real.On(Any()).Return(map[string]any{"choice": "A"})
+
+	recorder := RecordGenerator(configuredGenerator{real})
+	result, err := recorder.StructuredGenerateContentWithUsage(ctx, []api.Part{api.TextPart("q"), api.ImagePart([]byte{1}, "image/png")}, nil)
+	if err != nil || result.Usage != usage {
+		t.Errorf("StructuredGenerateContentWithUsage() usage = %+v, %v, want %+v", result.Usage, err, usage)
+	}
+	if config := recorder.GenerationConfig(); !reflect.DeepEqual(config, map[string]any{"model": "m"}) {
+		t.Errorf("GenerationConfig() = %v, want the wrapped generator's config", config)
+	}
+
+	// A text-only generator reports neither, and cannot take attachments
+	text := RecordGenerator(textOnlyGenerator{real})
+	if config := text.GenerationConfig(); config != nil {
+		t.Errorf("GenerationConfig() = %v, want nil", config)
+	}
+	result, err = text.StructuredGenerateContentWithUsage(ctx, []api.Part{api.TextPart("q")}, nil)
+	if err != nil || result.Data["choice"] != "A" || result.Usage != (api.Usage{}) {
+		t.Errorf("StructuredGenerateContentWithUsage() = %+v, %v, want choice A without usage", result, err)
+	}
+	if _, err := text.StructuredGenerateContent(ctx, []api.Part{api.TextPart("q"), api.ImagePart([]byte{1}, "image/png")}, nil); !errors.Is(err, api.ErrMultimodalNotSupported) {
+		t.Errorf("StructuredGenerateContent() error = %v, want api.ErrMultimodalNotSupported", err)
+	}
+}
+
+// textOnlyGenerator hides everything but LLMGenerator
+type textOnlyGenerator struct{ llm api.LLMGenerator }
+
+func (g textOnlyGenerator) StructuredGenerate(ctx context.Context, prompt string, schema map[string]interface{}) (map[string]interface{}, error) {
+	return g.llm.StructuredGenerate(ctx, prompt, schema)
+}
+
+func TestRecordReplay(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	t.Run("generator", func(t *testing.T) {
+		real := NewGenerator()
+		real.On(Exact("q")).Return(map[string]any{"answer": "first"}).Times(1)
+		real.On(Exact("q")).Return(map[string]any{"answer": "second"})
+		real.On(Exact("broken")).ReturnError(errors.New("quota exceeded"))
+
+		recorder := RecordGenerator(real)
+		recorder.StructuredGenerate(ctx, "q", nil)
+		recorder.StructuredGenerate(ctx, "q", nil)
+		recorder.StructuredGenerate(ctx, "broken", nil)
+		path := filepath.Join(dir, "generator.json")
+		if err := recorder.Save(path); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+
+		replayed, err := ReplayGenerator(path)
+		if err != nil {
+			t.Fatalf("ReplayGenerator() error = %v", err)
+		}
+		var answers []any
+		for range 3 {
+			got, _ := replayed.StructuredGenerate(ctx, "q", nil)
+			answers = append(answers, got["answer"])
+		}
+		if want := []any{"first", "second", "second"}; !reflect.DeepEqual(answers, want) {
+			t.Errorf("answers = %v, want %v", answers, want)
+		}
+		if _, err := replayed.StructuredGenerate(ctx, "broken", nil); err == nil || err.Error() != "quota exceeded" {
+			t.Errorf("error = %v, want quota exceeded", err)
+		}
+		if _, err := replayed.StructuredGenerate(ctx, "unrecorded", nil); !errors.Is(err, ErrNoResponse) {
+			t.Errorf("error = %v, want ErrNoResponse", err)
+		}
+	})
+
+	t.Run("embedder", func(t *testing.T) {
+		real := NewEmbedder()
+		real.On(Any()).Return([]float64{0.6, 0.8})
+
+		recorder := RecordEmbedder(real)
+		recorder.Embed(ctx, "text")
+		path := filepath.Join(dir, "embedder.json")
+		if err := recorder.Save(path); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+
+		replayed, err := ReplayEmbedder(path)
+		if err != nil {
+			t.Fatalf("ReplayEmbedder() error = %v", err)
+		}
+		if got, err := replayed.Embed(ctx, "text"); err != nil || !reflect.DeepEqual(got, []float64{0.6, 0.8}) {
+			t.Errorf("Embed() = %v, %v, want [0.6 0.8]", got, err)
+		}
+	})
+
+	t.Run("moderation", func(t *testing.T) {
+		real := NewModerationProvider()
+		real.On(Contains("idiot")).Return(Categories(map[string]float64{"Insult": 0.9, "Toxic": 0.7}))
+
+		recorder := RecordModerationProvider(real)
+		recorder.Moderate(ctx, "you idiot")
+		path := filepath.Join(dir, "moderation.json")
+		if err := recorder.Save(path); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+
+		replayed, err := ReplayModerationProvider(path)
+		if err != nil {
+			t.Fatalf("ReplayModerationProvider() error = %v", err)
+		}
+		got, err := replayed.Moderate(ctx, "you idiot")
+		want := []api.ModerationCategory{{Name: "Toxic", Confidence: 0.7}, {Name: "Insult", Confidence: 0.9}}
+		if err != nil || !reflect.DeepEqual(got.Categories, want) {
+			t.Errorf("Moderate() = %+v, %v, want %v", got, err, want)
+		}
+	})
+
+	if _, err := ReplayEmbedder(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("ReplayEmbedder() error = nil for a missing fixture")
+	}
+}
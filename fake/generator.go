@@ -0,0 +1,193 @@
+package fake
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// Generator is a scriptable api.LLMGenerator
+// It also accepts multimodal content and reports the configured usage, so it can stand in for gemini.Generator
+type Generator struct {
+	script script[map[string]any]
+	usage  api.Usage
+
+	mu    sync.Mutex
+	calls []GeneratorCall
+}
+
+// GeneratorCall is a recorded call to a Generator
+type GeneratorCall struct {
+	// Prompt is the prompt, or the text parts joined by newlines for multimodal calls
+	Prompt string
+	// Parts are the content parts of the call
+	Parts []api.Part
+	// Schema is the requested response schema
+	Schema map[string]any
+	// Response and Err are what the call returned
+	Response map[string]any
+	Err      error
+}
+
+// GeneratorOptions configures a Generator
+type GeneratorOptions struct {
+	latency time.Duration
+	usage   api.Usage
+}
+
+// WithGeneratorLatency delays every response by latency, in addition to per-rule latency
+func WithGeneratorLatency(latency time.Duration) func(*GeneratorOptions) {
+	return func(opts *GeneratorOptions) {
+		opts.latency = latency
+	}
+}
+
+// WithUsage sets the token usage reported for every call
+func WithUsage(usage api.Usage) func(*GeneratorOptions) {
+	return func(opts *GeneratorOptions) {
+		opts.usage = usage
+	}
+}
+
+// NewGenerator creates a Generator without rules; calls fail with ErrNoResponse until rules are added with On
+func NewGenerator(opts ...func(*GeneratorOptions)) *Generator {
+	options := GeneratorOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	g := &Generator{usage: options.usage}
+	g.script.latency = options.latency
+	return g
+}
+
+// On adds a rule for prompts accepted by match
+// Rules are tried in the order they were added, e.g.
+// g.On(fake.Contains("capital of France")).Return(map[string]any{"choice": "A"})
+func (g *Generator) On(match Matcher) *Rule[map[string]any] {
+	return g.script.on(match)
+}
+
+// Calls returns the calls made so far
+func (g *Generator) Calls() []GeneratorCall {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]GeneratorCall(nil), g.calls...)
+}
+
+// StructuredGenerate implements LLMGenerator.StructuredGenerate
+func (g *Generator) StructuredGenerate(ctx context.Context, prompt string, schema map[string]interface{}) (map[string]interface{}, error) {
+	result, err := g.StructuredGenerateContentWithUsage(ctx, []api.Part{api.TextPart(prompt)}, schema)
+	return result.Data, err
+}
+
+// StructuredGenerateContent implements MultimodalGenerator.StructuredGenerateContent
+func (g *Generator) StructuredGenerateContent(ctx context.Context, parts []api.Part, schema map[string]interface{}) (map[string]interface{}, error) {
+	result, err := g.StructuredGenerateContentWithUsage(ctx, parts, schema)
+	return result.Data, err
+}
+
+// StructuredGenerateContentWithUsage implements UsageReportingGenerator.StructuredGenerateContentWithUsage
+// Rules match against the text parts joined by newlines
+func (g *Generator) StructuredGenerateContentWithUsage(ctx context.Context, parts []api.Part, schema map[string]interface{}) (api.GenerateResult, error) {
+	prompt := promptText(parts)
+	response, err := g.script.respond(ctx, prompt)
+
+	g.mu.Lock()
+	g.calls = append(g.calls, GeneratorCall{Prompt: prompt, Parts: parts, Schema: schema, Response: response, Err: err})
+	g.mu.Unlock()
+
+	if err != nil {
+		return api.GenerateResult{}, err
+	}
+	return api.GenerateResult{Data: response, Usage: g.usage}, nil
+}
+
+// promptText joins the text parts of a prompt
+func promptText(parts []api.Part) string {
+	texts := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// GeneratorRecorder wraps a real generator and records its responses for ReplayGenerator
+// Token usage and generation settings reported by the wrapped generator are passed through
+type GeneratorRecorder struct {
+	llm      api.LLMGenerator
+	recorder recorder[map[string]any]
+}
+
+// RecordGenerator returns a recorder that passes calls through to llm
+func RecordGenerator(llm api.LLMGenerator) *GeneratorRecorder {
+	return &GeneratorRecorder{llm: llm}
+}
+
+// StructuredGenerate implements LLMGenerator.StructuredGenerate
+func (r *GeneratorRecorder) StructuredGenerate(ctx context.Context, prompt string, schema map[string]interface{}) (map[string]interface{}, error) {
+	response, err := r.llm.StructuredGenerate(ctx, prompt, schema)
+	r.recorder.record(prompt, response, err)
+	return response, err
+}
+
+// StructuredGenerateContent implements MultimodalGenerator.StructuredGenerateContent
+// Fixtures are keyed by the text parts only
+func (r *GeneratorRecorder) StructuredGenerateContent(ctx context.Context, parts []api.Part, schema map[string]interface{}) (map[string]interface{}, error) {
+	result, err := r.StructuredGenerateContentWithUsage(ctx, parts, schema)
+	return result.Data, err
+}
+
+// StructuredGenerateContentWithUsage implements UsageReportingGenerator.StructuredGenerateContentWithUsage
+// Usage is passed through when the wrapped generator reports it. Otherwise text-only content is sent with
+// StructuredGenerate, and other content requires the wrapped generator to implement api.MultimodalGenerator
+func (r *GeneratorRecorder) StructuredGenerateContentWithUsage(ctx context.Context, parts []api.Part, schema map[string]interface{}) (api.GenerateResult, error) {
+	var result api.GenerateResult
+	var err error
+	if reporting, ok := r.llm.(api.UsageReportingGenerator); ok {
+		result, err = reporting.StructuredGenerateContentWithUsage(ctx, parts, schema)
+	} else if multimodal, ok := r.llm.(api.MultimodalGenerator); ok {
+		result.Data, err = multimodal.StructuredGenerateContent(ctx, parts, schema)
+	} else if len(parts) == 1 && len(parts[0].Data) == 0 {
+		result.Data, err = r.llm.StructuredGenerate(ctx, parts[0].Text, schema)
+	} else {
+		return api.GenerateResult{}, api.ErrMultimodalNotSupported
+	}
+	r.recorder.record(promptText(parts), result.Data, err)
+	return result, err
+}
+
+// GenerationConfig passes through the wrapped generator's settings, if it reports them
+func (r *GeneratorRecorder) GenerationConfig() map[string]any {
+	if reporter, ok := r.llm.(api.GenerationConfigReporter); ok {
+		return reporter.GenerationConfig()
+	}
+	return nil
+}
+
+// Save writes the recorded calls to a JSON fixture at path
+func (r *GeneratorRecorder) Save(path string) error {
+	return r.recorder.save(path)
+}
+
+// ReplayGenerator creates a Generator that answers the prompts recorded in the fixture at path
+func ReplayGenerator(path string, opts ...func(*GeneratorOptions)) (*Generator, error) {
+	g := NewGenerator(opts...)
+	if err := replay(path, &g.script); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Verify that Generator and GeneratorRecorder implement the generator interfaces
+var (
+	_ api.MultimodalGenerator     = (*Generator)(nil)
+	_ api.UsageReportingGenerator = (*Generator)(nil)
+	_ api.MultimodalGenerator      = (*GeneratorRecorder)(nil)
+	_ api.UsageReportingGenerator  = (*GeneratorRecorder)(nil)
+	_ api.GenerationConfigReporter = (*GeneratorRecorder)(nil)
+)
@@ -0,0 +1,119 @@
+package fake
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// ModerationProvider is a scriptable api.ModerationProvider
+type ModerationProvider struct {
+	script script[*api.ModerationResult]
+
+	mu    sync.Mutex
+	calls []ModerationCall
+}
+
+// ModerationCall is a recorded call to a ModerationProvider
+type ModerationCall struct {
+	Content  string
+	Response *api.ModerationResult
+	Err      error
+}
+
+// ModerationOptions configures a ModerationProvider
+type ModerationOptions struct {
+	latency time.Duration
+}
+
+// WithModerationLatency delays every response by latency, in addition to per-rule latency
+func WithModerationLatency(latency time.Duration) func(*ModerationOptions) {
+	return func(opts *ModerationOptions) {
+		opts.latency = latency
+	}
+}
+
+// NewModerationProvider creates a ModerationProvider without rules; calls fail with ErrNoResponse until rules are added with On
+func NewModerationProvider(opts ...func(*ModerationOptions)) *ModerationProvider {
+	options := ModerationOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	p := &ModerationProvider{}
+	p.script.latency = options.latency
+	return p
+}
+
+// On adds a rule for content accepted by match
+func (p *ModerationProvider) On(match Matcher) *Rule[*api.ModerationResult] {
+	return p.script.on(match)
+}
+
+// Categories is a shorthand for a moderation result with the given category confidences
+func Categories(confidences map[string]float64) *api.ModerationResult {
+	result := &api.ModerationResult{}
+	for _, name := range api.ModerationCategories {
+		if confidence, ok := confidences[name]; ok {
+			result.Categories = append(result.Categories, api.ModerationCategory{Name: name, Confidence: confidence})
+		}
+	}
+	return result
+}
+
+// Calls returns the calls made so far
+func (p *ModerationProvider) Calls() []ModerationCall {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]ModerationCall(nil), p.calls...)
+}
+
+// Moderate implements ModerationProvider.Moderate
+func (p *ModerationProvider) Moderate(ctx context.Context, content string) (*api.ModerationResult, error) {
+	response, err := p.script.respond(ctx, content)
+
+	p.mu.Lock()
+	p.calls = append(p.calls, ModerationCall{Content: content, Response: response, Err: err})
+	p.mu.Unlock()
+
+	return response, err
+}
+
+// ModerationRecorder wraps a real provider and records its results for ReplayModerationProvider
+type ModerationRecorder struct {
+	provider api.ModerationProvider
+	recorder recorder[*api.ModerationResult]
+}
+
+// RecordModerationProvider returns a recorder that passes calls through to provider
+func RecordModerationProvider(provider api.ModerationProvider) *ModerationRecorder {
+	return &ModerationRecorder{provider: provider}
+}
+
+// Moderate implements ModerationProvider.Moderate
+func (r *ModerationRecorder) Moderate(ctx context.Context, content string) (*api.ModerationResult, error) {
+	response, err := r.provider.Moderate(ctx, content)
+	r.recorder.record(content, response, err)
+	return response, err
+}
+
+// Save writes the recorded calls to a JSON fixture at path
+func (r *ModerationRecorder) Save(path string) error {
+	return r.recorder.save(path)
+}
+
+// ReplayModerationProvider creates a ModerationProvider that answers the content recorded in the fixture at path
+func ReplayModerationProvider(path string, opts ...func(*ModerationOptions)) (*ModerationProvider, error) {
+	p := NewModerationProvider(opts...)
+	if err := replay(path, &p.script); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Verify that ModerationProvider and ModerationRecorder implement goeval.ModerationProvider
+var (
+	_ api.ModerationProvider = (*ModerationProvider)(nil)
+	_ api.ModerationProvider = (*ModerationRecorder)(nil)
+)
@@ -3,62 +3,245 @@ package gemini
 import (
 	"context"
 	"fmt"
+	"math"
 
 	"github.com/datar-psa/goeval/api"
 	"google.golang.org/genai"
 )
 
-// Embedder wraps a genai.Client to implement the Embedder interface
+// Default request limits for batch embedding
+// Batching is opt-in: some models, such as gemini-embedding-001 on Vertex AI, accept a single text
+// per request. 60,000 characters stays below the 20,000 tokens Vertex AI accepts per request
+const (
+	defaultMaxBatchSize  = 1
+	defaultMaxBatchChars = 60_000
+)
+
+// Embedder wraps a genai.Client to implement the Embedder, BatchEmbedder and BatchRequestCounter interfaces
 type Embedder struct {
 	client    *genai.Client
 	modelName string
+	options   EmbedderOptions
+}
+
+// TaskType tells the embedding model how the vectors will be used
+type TaskType string
+
+// Embedding task types supported by Gemini and Vertex AI embedding models
+const (
+	TaskSemanticSimilarity TaskType = "SEMANTIC_SIMILARITY"
+	TaskRetrievalQuery     TaskType = "RETRIEVAL_QUERY"
+	TaskRetrievalDocument  TaskType = "RETRIEVAL_DOCUMENT"
+	TaskClassification     TaskType = "CLASSIFICATION"
+	TaskClustering         TaskType = "CLUSTERING"
+	TaskQuestionAnswering  TaskType = "QUESTION_ANSWERING"
+	TaskFactVerification   TaskType = "FACT_VERIFICATION"
+	TaskCodeRetrievalQuery TaskType = "CODE_RETRIEVAL_QUERY"
+)
+
+// EmbedderOptions configures an Embedder
+// Settings that are not set are left to the model defaults
+type EmbedderOptions struct {
+	maxBatchSize         int
+	maxBatchChars        int
+	taskType             TaskType
+	title                string
+	outputDimensionality *int32
+	autoTruncate         *bool
+}
+
+// WithTaskType sets the task type the embeddings are optimized for (e.g., TaskSemanticSimilarity)
+func WithTaskType(taskType TaskType) func(*EmbedderOptions) {
+	return func(opts *EmbedderOptions) {
+		opts.taskType = taskType
+	}
+}
+
+// WithTitle sets the document title; only applicable with TaskRetrievalDocument
+func WithTitle(title string) func(*EmbedderOptions) {
+	return func(opts *EmbedderOptions) {
+		opts.title = title
+	}
+}
+
+// WithOutputDimensionality reduces embeddings to the given number of dimensions
+// Reduced vectors are re-normalized to unit length before they are returned
+func WithOutputDimensionality(dimensions int32) func(*EmbedderOptions) {
+	return func(opts *EmbedderOptions) {
+		opts.outputDimensionality = &dimensions
+	}
+}
+
+// WithAutoTruncate sets whether the model silently truncates inputs longer than its maximum sequence length
+// With false, over-long inputs are rejected with an error. Vertex AI only; when not set the server default
+// (truncate) applies
+func WithAutoTruncate(enabled bool) func(*EmbedderOptions) {
+	return func(opts *EmbedderOptions) {
+		opts.autoTruncate = &enabled
+	}
+}
+
+// WithMaxBatchSize sets the maximum number of texts sent in one request (default: 1)
+// Raise it for models that accept several inputs, e.g. 100 for text-embedding-005; gemini-embedding-001
+// on Vertex AI accepts a single text per request
+func WithMaxBatchSize(size int) func(*EmbedderOptions) {
+	return func(opts *EmbedderOptions) {
+		opts.maxBatchSize = size
+	}
+}
+
+// WithMaxBatchChars sets the maximum total characters sent in one request (default: 60,000)
+func WithMaxBatchChars(chars int) func(*EmbedderOptions) {
+	return func(opts *EmbedderOptions) {
+		opts.maxBatchChars = chars
+	}
 }
 
 // NewEmbedder creates a new Gemini embedder
 // client: genai.Client from google.golang.org/genai
 // modelName: the embedding model to use (e.g., "text-embedding-005")
-func NewEmbedder(client *genai.Client, modelName string) *Embedder {
-	return &Embedder{
+// opts: optional settings (e.g., WithTaskType(TaskSemanticSimilarity), WithOutputDimensionality(256))
+func NewEmbedder(client *genai.Client, modelName string, opts ...func(*EmbedderOptions)) *Embedder {
+	e := &Embedder{
 		client:    client,
 		modelName: modelName,
+		options: EmbedderOptions{
+			maxBatchSize:  defaultMaxBatchSize,
+			maxBatchChars: defaultMaxBatchChars,
+		},
 	}
+	for _, opt := range opts {
+		opt(&e.options)
+	}
+	return e
+}
+
+// contentConfig builds the request config with the configured settings
+func (e *Embedder) contentConfig() *genai.EmbedContentConfig {
+	config := &genai.EmbedContentConfig{
+		TaskType:             string(e.options.taskType),
+		Title:                e.options.title,
+		OutputDimensionality: e.options.outputDimensionality,
+	}
+	switch {
+	case e.options.autoTruncate == nil:
+	case *e.options.autoTruncate:
+		config.AutoTruncate = true
+	default:
+		// AutoTruncate is omitted from the request when false, so disabling it is sent as a raw parameter
+		config.HTTPOptions = &genai.HTTPOptions{
+			ExtraBody: map[string]any{"parameters": map[string]any{"autoTruncate": false}},
+		}
+	}
+	return config
 }
 
 // Embed implements Embedder.Embed
 // Note: This uses the Embedding API which is separate from the text generation API
 func (e *Embedder) Embed(ctx context.Context, text string) ([]float64, error) {
+	embeddings, err := e.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// EmbedBatch implements BatchEmbedder.EmbedBatch
+// Texts are split into as few requests as the batch size and character limits allow
+func (e *Embedder) EmbedBatch(ctx context.Context, texts []string) ([][]float64, error) {
+	embeddings := make([][]float64, 0, len(texts))
+	for _, batch := range e.batches(texts) {
+		batchEmbeddings, err := e.embed(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		embeddings = append(embeddings, batchEmbeddings...)
+	}
+	return embeddings, nil
+}
+
+// BatchRequests implements BatchRequestCounter.BatchRequests
+func (e *Embedder) BatchRequests(texts []string) int {
+	return len(e.batches(texts))
+}
+
+// batches splits texts into consecutive groups within the request limits
+// A single text longer than the character limit is sent on its own
+func (e *Embedder) batches(texts []string) [][]string {
+	var batches [][]string
+	var current []string
+	chars := 0
+	for _, text := range texts {
+		full := len(current) > 0 && (len(current) >= e.options.maxBatchSize || chars+len(text) > e.options.maxBatchChars)
+		if full {
+			batches = append(batches, current)
+			current, chars = nil, 0
+		}
+		current = append(current, text)
+		chars += len(text)
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
+
+// embed sends one embedding request for texts
+func (e *Embedder) embed(ctx context.Context, texts []string) ([][]float64, error) {
 	// Prepare content for embedding
-	contents := []*genai.Content{
-		{
+	contents := make([]*genai.Content, len(texts))
+	for i, text := range texts {
+		contents[i] = &genai.Content{
 			Parts: []*genai.Part{
 				{Text: text},
 			},
-		},
+		}
 	}
 
 	// Call the embeddings endpoint using the EmbedContent method
-	result, err := e.client.Models.EmbedContent(ctx, e.modelName, contents, &genai.EmbedContentConfig{})
+	result, err := e.client.Models.EmbedContent(ctx, e.modelName, contents, e.contentConfig())
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate embedding: %w", err)
 	}
 
-	if len(result.Embeddings) == 0 {
-		return nil, fmt.Errorf("no embeddings returned")
+	if len(result.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(result.Embeddings))
 	}
 
-	if len(result.Embeddings[0].Values) == 0 {
-		return nil, fmt.Errorf("empty embedding vector")
-	}
+	embeddings := make([][]float64, len(texts))
+	for i, embedding := range result.Embeddings {
+		if embedding == nil || len(embedding.Values) == 0 {
+			return nil, fmt.Errorf("empty embedding vector")
+		}
 
-	// Convert []float32 to []float64
-	values := result.Embeddings[0].Values
-	embedding := make([]float64, len(values))
-	for i, v := range values {
-		embedding[i] = float64(v)
+		embeddings[i] = normalize(embedding.Values)
 	}
 
-	return embedding, nil
+	return embeddings, nil
 }
 
-// Verify that Embedder implements goeval.Embedder
-var _ api.Embedder = (*Embedder)(nil)
+// normalize converts v to []float64 scaled to unit length, as the api.Embedder contract promises
+// Reduced-dimensionality embeddings are not normalized by the model
+func normalize(v []float32) []float64 {
+	normalized := make([]float64, len(v))
+	var sum float64
+	for i, x := range v {
+		normalized[i] = float64(x)
+		sum += normalized[i] * normalized[i]
+	}
+	if sum == 0 {
+		return normalized
+	}
+	norm := math.Sqrt(sum)
+	for i := range normalized {
+		normalized[i] /= norm
+	}
+	return normalized
+}
+
+// Verify that Embedder implements goeval.Embedder, goeval.BatchEmbedder and goeval.BatchRequestCounter
+var (
+	_ api.Embedder            = (*Embedder)(nil)
+	_ api.BatchEmbedder       = (*Embedder)(nil)
+	_ api.BatchRequestCounter = (*Embedder)(nil)
+)
@@ -13,65 +13,230 @@ import (
 type Generator struct {
 	client    *genai.Client
 	modelName string
+	options   GeneratorOptions
+}
+
+// GeneratorOptions configures generation settings of a Generator
+// Settings that are not set are left to the model defaults
+type GeneratorOptions struct {
+	temperature       *float32
+	topP              *float32
+	seed              *int32
+	maxOutputTokens   int32
+	systemInstruction string
+	safetySettings    []*genai.SafetySetting
+	thinkingBudget    *int32
+}
+
+// WithTemperature sets the sampling temperature; use 0 for the most deterministic judge output
+func WithTemperature(temperature float32) func(*GeneratorOptions) {
+	return func(opts *GeneratorOptions) {
+		opts.temperature = &temperature
+	}
+}
+
+// WithTopP sets nucleus sampling probability mass
+func WithTopP(topP float32) func(*GeneratorOptions) {
+	return func(opts *GeneratorOptions) {
+		opts.topP = &topP
+	}
+}
+
+// WithSeed sets the random seed used for decoding
+func WithSeed(seed int32) func(*GeneratorOptions) {
+	return func(opts *GeneratorOptions) {
+		opts.seed = &seed
+	}
+}
+
+// WithMaxOutputTokens limits the number of tokens in a response
+func WithMaxOutputTokens(maxOutputTokens int32) func(*GeneratorOptions) {
+	return func(opts *GeneratorOptions) {
+		opts.maxOutputTokens = maxOutputTokens
+	}
+}
+
+// WithSystemInstruction sets a system instruction sent with every request
+func WithSystemInstruction(instruction string) func(*GeneratorOptions) {
+	return func(opts *GeneratorOptions) {
+		opts.systemInstruction = instruction
+	}
+}
+
+// WithSafetySettings sets the safety settings sent with every request
+func WithSafetySettings(settings ...*genai.SafetySetting) func(*GeneratorOptions) {
+	return func(opts *GeneratorOptions) {
+		opts.safetySettings = settings
+	}
+}
+
+// WithThinkingBudget sets the thinking budget in tokens; 0 disables thinking on models that allow it
+func WithThinkingBudget(budget int32) func(*GeneratorOptions) {
+	return func(opts *GeneratorOptions) {
+		opts.thinkingBudget = &budget
+	}
 }
 
 // NewGenerator creates a new Gemini generator
 // client: genai.Client from google.golang.org/genai
 // modelName: the model to use (e.g., "gemini-2.5-flash")
-func NewGenerator(client *genai.Client, modelName string) *Generator {
-	return &Generator{
+// opts: optional generation settings (e.g., WithTemperature(0), WithSeed(42))
+func NewGenerator(client *genai.Client, modelName string, opts ...func(*GeneratorOptions)) *Generator {
+	g := &Generator{
 		client:    client,
 		modelName: modelName,
 	}
+	for _, opt := range opts {
+		opt(&g.options)
+	}
+	return g
+}
+
+// GenerationConfig implements GenerationConfigReporter.GenerationConfig
+// Only the model and settings that were explicitly configured are included
+func (g *Generator) GenerationConfig() map[string]any {
+	config := map[string]any{"model": g.modelName}
+	if g.options.temperature != nil {
+		config["temperature"] = float64(*g.options.temperature)
+	}
+	if g.options.topP != nil {
+		config["top_p"] = float64(*g.options.topP)
+	}
+	if g.options.seed != nil {
+		config["seed"] = int(*g.options.seed)
+	}
+	if g.options.maxOutputTokens > 0 {
+		config["max_output_tokens"] = int(g.options.maxOutputTokens)
+	}
+	if g.options.systemInstruction != "" {
+		config["system_instruction"] = g.options.systemInstruction
+	}
+	if len(g.options.safetySettings) > 0 {
+		settings := make([]map[string]string, 0, len(g.options.safetySettings))
+		for _, setting := range g.options.safetySettings {
+			settings = append(settings, map[string]string{
+				"category":  string(setting.Category),
+				"threshold": string(setting.Threshold),
+			})
+		}
+		config["safety_settings"] = settings
+	}
+	if g.options.thinkingBudget != nil {
+		config["thinking_budget"] = int(*g.options.thinkingBudget)
+	}
+	return config
+}
+
+// contentConfig builds the request config for a structured response with the configured settings
+func (g *Generator) contentConfig(schema *genai.Schema) *genai.GenerateContentConfig {
+	config := &genai.GenerateContentConfig{
+		ResponseMIMEType: "application/json",
+		ResponseSchema:   schema,
+		Temperature:      g.options.temperature,
+		TopP:             g.options.topP,
+		Seed:             g.options.seed,
+		MaxOutputTokens:  g.options.maxOutputTokens,
+		SafetySettings:   g.options.safetySettings,
+	}
+	if g.options.systemInstruction != "" {
+		config.SystemInstruction = genai.NewContentFromText(g.options.systemInstruction, genai.RoleUser)
+	}
+	if g.options.thinkingBudget != nil {
+		config.ThinkingConfig = &genai.ThinkingConfig{ThinkingBudget: g.options.thinkingBudget}
+	}
+	return config
 }
 
 // StructuredGenerate implements LLMGenerator.StructuredGenerate
 func (g *Generator) StructuredGenerate(ctx context.Context, prompt string, schema map[string]interface{}) (map[string]interface{}, error) {
+	result, err := g.generate(ctx, []*genai.Part{{Text: prompt}}, schema)
+	return result.Data, err
+}
+
+// StructuredGenerateContent implements MultimodalGenerator.StructuredGenerateContent
+// Binary parts are sent as inline data
+func (g *Generator) StructuredGenerateContent(ctx context.Context, parts []api.Part, schema map[string]interface{}) (map[string]interface{}, error) {
+	result, err := g.StructuredGenerateContentWithUsage(ctx, parts, schema)
+	return result.Data, err
+}
+
+// StructuredGenerateContentWithUsage implements UsageReportingGenerator.StructuredGenerateContentWithUsage
+func (g *Generator) StructuredGenerateContentWithUsage(ctx context.Context, parts []api.Part, schema map[string]interface{}) (api.GenerateResult, error) {
+	genaiParts := make([]*genai.Part, 0, len(parts))
+	for i, part := range parts {
+		switch {
+		case len(part.Data) > 0:
+			if part.MIMEType == "" {
+				return api.GenerateResult{}, fmt.Errorf("part %d: MIME type is required for inline data", i)
+			}
+			genaiParts = append(genaiParts, genai.NewPartFromBytes(part.Data, part.MIMEType))
+		case part.Text != "":
+			genaiParts = append(genaiParts, genai.NewPartFromText(part.Text))
+		default:
+			return api.GenerateResult{}, fmt.Errorf("part %d: empty part", i)
+		}
+	}
+	if len(genaiParts) == 0 {
+		return api.GenerateResult{}, fmt.Errorf("no content parts")
+	}
+	return g.generate(ctx, genaiParts, schema)
+}
+
+// generate requests a JSON response matching schema for the given user content parts
+// Usage is reported even when the response cannot be parsed, since the tokens were spent
+func (g *Generator) generate(ctx context.Context, parts []*genai.Part, schema map[string]interface{}) (api.GenerateResult, error) {
 	// Convert schema to genai.Schema
 	genaiSchema, err := g.convertToGenaiSchema(schema)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert schema: %w", err)
+		return api.GenerateResult{}, fmt.Errorf("failed to convert schema: %w", err)
 	}
 
 	content := &genai.Content{
-		Role: "user",
-		Parts: []*genai.Part{
-			{Text: prompt},
-		},
+		Role:  "user",
+		Parts: parts,
 	}
 
 	resp, err := g.client.Models.GenerateContent(
 		ctx,
 		g.modelName,
 		[]*genai.Content{content},
-		&genai.GenerateContentConfig{
-			ResponseMIMEType: "application/json",
-			ResponseSchema:   genaiSchema,
-		},
+		g.contentConfig(genaiSchema),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate content: %w", err)
+		return api.GenerateResult{}, fmt.Errorf("failed to generate content: %w", err)
 	}
 
+	result := api.GenerateResult{Usage: g.usage(resp)}
+
 	if len(resp.Candidates) == 0 {
-		return nil, fmt.Errorf("no candidates returned")
+		return result, fmt.Errorf("no candidates returned")
 	}
 
-	if len(resp.Candidates[0].Content.Parts) == 0 {
-		return nil, fmt.Errorf("no parts in response")
+	if resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
+		return result, fmt.Errorf("no parts in response")
 	}
 
 	responseText := resp.Candidates[0].Content.Parts[0].Text
 
 	// Parse the JSON response
-	var result map[string]interface{}
-	if err := json.Unmarshal([]byte(responseText), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON response: %w, response: %s", err, responseText)
+	if err := json.Unmarshal([]byte(responseText), &result.Data); err != nil {
+		return result, fmt.Errorf("failed to parse JSON response: %w, response: %s", err, responseText)
 	}
 
 	return result, nil
 }
 
+// usage converts the response usage metadata into api.Usage
+func (g *Generator) usage(resp *genai.GenerateContentResponse) api.Usage {
+	usage := api.Usage{Model: g.modelName, Calls: 1}
+	if resp.UsageMetadata != nil {
+		usage.PromptTokens = int(resp.UsageMetadata.PromptTokenCount)
+		usage.OutputTokens = int(resp.UsageMetadata.CandidatesTokenCount)
+		usage.ThinkingTokens = int(resp.UsageMetadata.ThoughtsTokenCount)
+	}
+	return usage
+}
+
 // convertToGenaiSchema converts a map[string]interface{} schema to genai.Schema
 func (g *Generator) convertToGenaiSchema(schema map[string]interface{}) (*genai.Schema, error) {
 	// Convert to JSON first, then unmarshal into genai.Schema
@@ -88,5 +253,10 @@ func (g *Generator) convertToGenaiSchema(schema map[string]interface{}) (*genai.
 	return &genaiSchema, nil
 }
 
-// Verify that Generator implements LLMGenerator
-var _ api.LLMGenerator = (*Generator)(nil)
+// Verify that Generator implements LLMGenerator and the optional generator interfaces
+var (
+	_ api.LLMGenerator             = (*Generator)(nil)
+	_ api.MultimodalGenerator      = (*Generator)(nil)
+	_ api.UsageReportingGenerator  = (*Generator)(nil)
+	_ api.GenerationConfigReporter = (*Generator)(nil)
+)
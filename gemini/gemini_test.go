@@ -0,0 +1,267 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+	"google.golang.org/genai"
+)
+
+func TestGenerator_ContentConfig(t *testing.T) {
+	schema := &genai.Schema{Type: genai.TypeObject}
+
+	// Without options the request config must stay exactly as before, so recorded requests still match
+	plain := NewGenerator(nil, "gemini-2.5-flash").contentConfig(schema)
+	got, _ := json.Marshal(plain)
+	want, _ := json.Marshal(&genai.GenerateContentConfig{ResponseMIMEType: "application/json", ResponseSchema: schema})
+	if string(got) != string(want) {
+		t.Errorf("contentConfig() without options = %s, want %s", got, want)
+	}
+
+	g := NewGenerator(nil, "gemini-2.5-flash",
+		WithTemperature(0),
+		WithTopP(0.9),
+		WithSeed(42),
+		WithMaxOutputTokens(256),
+		WithSystemInstruction("You are a strict grader."),
+		WithSafetySettings(&genai.SafetySetting{Category: genai.HarmCategoryHarassment, Threshold: genai.HarmBlockThresholdBlockNone}),
+		WithThinkingBudget(0),
+	)
+	config := g.contentConfig(schema)
+	if config.Temperature == nil || *config.Temperature != 0 {
+		t.Errorf("Temperature = %v, want 0", config.Temperature)
+	}
+	if config.Seed == nil || *config.Seed != 42 {
+		t.Errorf("Seed = %v, want 42", config.Seed)
+	}
+	if config.MaxOutputTokens != 256 {
+		t.Errorf("MaxOutputTokens = %v, want 256", config.MaxOutputTokens)
+	}
+	if config.SystemInstruction == nil || config.SystemInstruction.Parts[0].Text != "You are a strict grader." {
+		t.Errorf("SystemInstruction = %+v", config.SystemInstruction)
+	}
+	if config.ThinkingConfig == nil || *config.ThinkingConfig.ThinkingBudget != 0 {
+		t.Errorf("ThinkingConfig = %+v, want budget 0", config.ThinkingConfig)
+	}
+
+	wantReport := map[string]any{
+		"model":              "gemini-2.5-flash",
+		"temperature":        0.0,
+		"top_p":              float64(float32(0.9)),
+		"seed":               42,
+		"max_output_tokens":  256,
+		"system_instruction": "You are a strict grader.",
+		"safety_settings": []map[string]string{
+			{"category": string(genai.HarmCategoryHarassment), "threshold": string(genai.HarmBlockThresholdBlockNone)},
+		},
+		"thinking_budget": 0,
+	}
+	if report := g.GenerationConfig(); !reflect.DeepEqual(report, wantReport) {
+		t.Errorf("GenerationConfig() = %v, want %v", report, wantReport)
+	}
+}
+
+func TestGenerator_StructuredGenerateContentInvalidParts(t *testing.T) {
+	g := NewGenerator(nil, "gemini-2.5-flash")
+
+	tests := []struct {
+		name    string
+		parts   []api.Part
+		wantErr string
+	}{
+		{name: "no parts", parts: nil, wantErr: "no content parts"},
+		{name: "empty part", parts: []api.Part{api.TextPart("question"), {}}, wantErr: "part 1: empty part"},
+		{name: "empty attachment", parts: []api.Part{api.ImagePart(nil, "image/png")}, wantErr: "part 0: empty part"},
+		{name: "missing MIME type", parts: []api.Part{{Data: []byte{1}}}, wantErr: "part 0: MIME type is required for inline data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := g.StructuredGenerateContent(context.Background(), tt.parts, map[string]interface{}{"type": "object"})
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("StructuredGenerateContent() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEmbedder_Batches(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []func(*EmbedderOptions)
+		texts []string
+		want  [][]string
+	}{
+		{
+			name:  "one text per request by default",
+			texts: []string{"a", "b", "c"},
+			want:  [][]string{{"a"}, {"b"}, {"c"}},
+		},
+		{
+			name:  "single batch",
+			opts:  []func(*EmbedderOptions){WithMaxBatchSize(100)},
+			texts: []string{"a", "b", "c"},
+			want:  [][]string{{"a", "b", "c"}},
+		},
+		{
+			name:  "split by size",
+			opts:  []func(*EmbedderOptions){WithMaxBatchSize(2)},
+			texts: []string{"a", "b", "c"},
+			want:  [][]string{{"a", "b"}, {"c"}},
+		},
+		{
+			name:  "split by characters",
+			opts:  []func(*EmbedderOptions){WithMaxBatchSize(100), WithMaxBatchChars(5)},
+			texts: []string{"abc", "de", "f", "ghijkl", "m"},
+			want:  [][]string{{"abc", "de"}, {"f"}, {"ghijkl"}, {"m"}},
+		},
+		{
+			name:  "empty",
+			texts: nil,
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewEmbedder(nil, "text-embedding-005", tt.opts...).batches(tt.texts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("batches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmbedder_SingleTextRequests(t *testing.T) {
+	var requestSizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Requests []json.RawMessage `json:"requests"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		requestSizes = append(requestSizes, len(body.Requests))
+		embeddings := make([]map[string]any, len(body.Requests))
+		for i := range embeddings {
+			embeddings[i] = map[string]any{"values": []float32{1, 0}}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"embeddings": embeddings})
+	}))
+	defer server.Close()
+
+	client, err := genai.NewClient(t.Context(), &genai.ClientConfig{
+		APIKey:      "test",
+		Backend:     genai.BackendGeminiAPI,
+		HTTPOptions: genai.HTTPOptions{BaseURL: server.URL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	embeddings, err := NewEmbedder(client, "gemini-embedding-001").EmbedBatch(t.Context(), []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("EmbedBatch() error = %v", err)
+	}
+	if len(embeddings) != 3 {
+		t.Errorf("EmbedBatch() returned %d embeddings, want 3", len(embeddings))
+	}
+	if !reflect.DeepEqual(requestSizes, []int{1, 1, 1}) {
+		t.Errorf("texts per request = %v, want [1 1 1]", requestSizes)
+	}
+}
+
+func TestEmbedder_AutoTruncate(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []func(*EmbedderOptions)
+		want any
+	}{
+		{name: "server default", want: nil},
+		{name: "enabled", opts: []func(*EmbedderOptions){WithAutoTruncate(true)}, want: true},
+		{name: "disabled", opts: []func(*EmbedderOptions){WithAutoTruncate(false)}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var parameters map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Parameters map[string]any `json:"parameters"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("invalid request body: %v", err)
+				}
+				parameters = body.Parameters
+				json.NewEncoder(w).Encode(map[string]any{
+					"predictions": []any{map[string]any{"embeddings": map[string]any{"values": []float32{1, 0}}}},
+				})
+			}))
+			defer server.Close()
+
+			client, err := genai.NewClient(t.Context(), &genai.ClientConfig{
+				Backend:     genai.BackendVertexAI,
+				Project:     "test",
+				Location:    "global",
+				HTTPClient:  server.Client(),
+				HTTPOptions: genai.HTTPOptions{BaseURL: server.URL},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := NewEmbedder(client, "text-embedding-005", tt.opts...).Embed(t.Context(), "text"); err != nil {
+				t.Fatalf("Embed() error = %v", err)
+			}
+			if got := parameters["autoTruncate"]; got != tt.want {
+				t.Errorf("parameters.autoTruncate = %v, want %v (parameters: %v)", got, tt.want, parameters)
+			}
+		})
+	}
+}
+
+func TestEmbedder_ContentConfig(t *testing.T) {
+	// No options keeps the empty config, so requests are unchanged
+	if got, _ := json.Marshal(NewEmbedder(nil, "text-embedding-005").contentConfig()); string(got) != "{}" {
+		t.Errorf("contentConfig() = %s, want {}", got)
+	}
+
+	e := NewEmbedder(nil, "gemini-embedding-001",
+		WithTaskType(TaskRetrievalDocument),
+		WithTitle("Leave policy"),
+		WithOutputDimensionality(256),
+		WithAutoTruncate(true),
+	)
+	config := e.contentConfig()
+	if config.TaskType != "RETRIEVAL_DOCUMENT" || config.Title != "Leave policy" || config.OutputDimensionality == nil || *config.OutputDimensionality != 256 || !config.AutoTruncate {
+		t.Errorf("contentConfig() = %+v, want configured settings", config)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []float32
+		want []float64
+	}{
+		{name: "scaled to unit length", in: []float32{3, 4}, want: []float64{0.6, 0.8}},
+		{name: "unit vector unchanged", in: []float32{0, 1}, want: []float64{0, 1}},
+		{name: "zero vector unchanged", in: []float32{0, 0}, want: []float64{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalize(tt.in)
+			for i := range tt.want {
+				if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+					t.Fatalf("normalize() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -5,7 +5,9 @@ go 1.25.2
 require (
 	cloud.google.com/go/language v1.14.6
 	github.com/areknoster/hypert v0.51.0
+	github.com/santhosh-tekuri/jsonschema/v6 v6.0.3
 	google.golang.org/genai v1.31.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
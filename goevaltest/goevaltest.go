@@ -0,0 +1,287 @@
+// Package goevaltest runs evals inside go test: score assertions, table-driven datasets with
+// a subtest per row, and golden files that fail on score regressions
+package goevaltest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// DefaultTolerance is the score drop allowed against a golden file when Options.Tolerance is not set
+const DefaultTolerance = 0.05
+
+// RequireScore scores in and stops the test unless the scorer succeeds with at least min
+func RequireScore(t testing.TB, scorer api.Scorer, in api.ScoreInputs, min float64) api.Score {
+	t.Helper()
+	score := scorer.Score(t.Context(), in)
+	if msg := checkScore(score, min); msg != "" {
+		t.Fatal(msg)
+	}
+	return score
+}
+
+// AssertScore is like RequireScore but lets the test continue after a failure
+func AssertScore(t testing.TB, scorer api.Scorer, in api.ScoreInputs, min float64) api.Score {
+	t.Helper()
+	score := scorer.Score(t.Context(), in)
+	if msg := checkScore(score, min); msg != "" {
+		t.Error(msg)
+	}
+	return score
+}
+
+// checkScore returns a failure message, or "" when score passes min
+func checkScore(score api.Score, min float64) string {
+	if score.Error != nil {
+		return fmt.Sprintf("%s: error: %v", score.Name, score.Error)
+	}
+	if score.Score < min {
+		msg := fmt.Sprintf("%s: score %.4f is below %.4f", score.Name, score.Score, min)
+		if explanation, ok := score.Metadata["explanation"].(string); ok && explanation != "" {
+			msg += "\nexplanation: " + explanation
+		}
+		return msg
+	}
+	return ""
+}
+
+// Row is one case of an eval dataset
+type Row struct {
+	// Name names the subtest (default: "row_<index>")
+	Name string
+	// Inputs are passed to every scorer
+	Inputs api.ScoreInputs
+	// MinScore overrides Options.MinScore for this row when set, and may lower it down to 0 (optional)
+	MinScore *float64
+}
+
+// Options configures Run
+type Options struct {
+	// MinScore is the score every scorer must reach on every row (default: 0, no threshold)
+	MinScore float64
+	// Golden is the path of a golden file with the expected score of each row and scorer (optional)
+	// With UPDATE_TESTS=true the file is rewritten from the current scores instead of being checked
+	Golden string
+	// Tolerance is how far a score may drop below its golden value (default: DefaultTolerance)
+	Tolerance float64
+	// Parallel runs rows as parallel subtests
+	Parallel bool
+}
+
+// GoldenScores maps row names to scorer names to scores, as stored in golden files
+type GoldenScores map[string]map[string]float64
+
+// Run scores every row with every scorer in a subtest named after the row
+// Rows fail when a scorer errors, scores below the threshold, or regresses against the golden file
+func Run(t *testing.T, rows []Row, scorers []api.Scorer, opts Options) {
+	t.Helper()
+
+	update := os.Getenv("UPDATE_TESTS") == "true"
+	var golden GoldenScores
+	if opts.Golden != "" && !update {
+		var err error
+		if golden, err = ReadGolden(opts.Golden); err != nil {
+			t.Fatalf("failed to read golden file: %v (run with UPDATE_TESTS=true to create it)", err)
+		}
+	}
+	tolerance := opts.Tolerance
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+
+	var mu sync.Mutex
+	got := make(GoldenScores, len(rows))
+	if opts.Golden != "" && update {
+		// Cleanup runs after all subtests, including parallel ones, have finished
+		t.Cleanup(func() {
+			if t.Failed() {
+				t.Logf("not updating golden file %s: test failed", opts.Golden)
+				return
+			}
+			if err := WriteGolden(opts.Golden, got); err != nil {
+				t.Errorf("failed to write golden file: %v", err)
+			}
+		})
+	}
+
+	names := make(map[string]bool, len(rows))
+	for i, row := range rows {
+		name := row.Name
+		if name == "" {
+			name = fmt.Sprintf("row_%d", i)
+		}
+		// Golden scores are keyed by row name, so every row needs its own
+		if names[name] {
+			t.Fatalf("duplicate row name %q", name)
+		}
+		names[name] = true
+		min := opts.MinScore
+		if row.MinScore != nil {
+			min = *row.MinScore
+		}
+
+		t.Run(name, func(t *testing.T) {
+			if opts.Parallel {
+				t.Parallel()
+			}
+
+			scores := make(map[string]float64, len(scorers))
+			for _, scorer := range scorers {
+				score := scorer.Score(t.Context(), row.Inputs)
+				if msg := checkScore(score, min); msg != "" {
+					t.Error(msg)
+				}
+				scores[uniqueName(scores, score.Name)] = score.Score
+			}
+
+			if golden != nil {
+				for _, msg := range compareGolden(golden[name], scores, tolerance) {
+					t.Error(msg)
+				}
+			}
+
+			mu.Lock()
+			got[name] = scores
+			mu.Unlock()
+		})
+	}
+
+	for _, name := range sortedKeys(golden) {
+		if !names[name] {
+			t.Errorf("%s: golden row is not in the dataset (run with UPDATE_TESTS=true to remove it)", name)
+		}
+	}
+}
+
+// uniqueName returns name, suffixed with #2, #3, ... when scores already has it
+func uniqueName(scores map[string]float64, name string) string {
+	unique := name
+	for n := 2; ; n++ {
+		if _, ok := scores[unique]; !ok {
+			return unique
+		}
+		unique = fmt.Sprintf("%s#%d", name, n)
+	}
+}
+
+// compareGolden returns a failure message for each scorer that is missing from golden or dropped beyond tolerance,
+// and for each golden score that no scorer produced, e.g. after a scorer was removed or renamed
+func compareGolden(golden map[string]float64, scores map[string]float64, tolerance float64) []string {
+	var msgs []string
+	for _, name := range sortedKeys(scores) {
+		want, ok := golden[name]
+		if !ok {
+			msgs = append(msgs, fmt.Sprintf("%s: no golden score (run with UPDATE_TESTS=true to record it)", name))
+			continue
+		}
+		if got := scores[name]; got < want-tolerance {
+			msgs = append(msgs, fmt.Sprintf("%s: score regressed from %.4f to %.4f (tolerance %.4f)", name, want, got, tolerance))
+		}
+	}
+	for _, name := range sortedKeys(golden) {
+		if _, ok := scores[name]; !ok {
+			msgs = append(msgs, fmt.Sprintf("%s: golden score %.4f was not produced (run with UPDATE_TESTS=true to remove it)", name, golden[name]))
+		}
+	}
+	return msgs
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ReadGolden reads a golden file written by WriteGolden
+// Duplicate row or scorer names are rejected rather than silently overwriting each other
+func ReadGolden(path string) (GoldenScores, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	golden := GoldenScores{}
+	err = decodeObject(data, func(row string, value json.RawMessage) error {
+		if _, ok := golden[row]; ok {
+			return fmt.Errorf("duplicate row %q", row)
+		}
+		scores := map[string]float64{}
+		err := decodeObject(value, func(scorer string, value json.RawMessage) error {
+			if _, ok := scores[scorer]; ok {
+				return fmt.Errorf("duplicate scorer %q in row %q", scorer, row)
+			}
+			var score float64
+			if err := json.Unmarshal(value, &score); err != nil {
+				return fmt.Errorf("row %q, scorer %q: %w", row, scorer, err)
+			}
+			scores[scorer] = score
+			return nil
+		})
+		golden[row] = scores
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid golden file %s: %w", path, err)
+	}
+	return golden, nil
+}
+
+// decodeObject calls fn for each member of a JSON object in order, keeping duplicate keys
+// A null value is read as an empty object
+func decodeObject(data []byte, fn func(key string, value json.RawMessage) error) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return nil
+	}
+	if token != json.Delim('{') {
+		return fmt.Errorf("expected an object, got %v", token)
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		if err := fn(token.(string), value); err != nil {
+			return err
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return fmt.Errorf("unexpected data after the object")
+	}
+	return nil
+}
+
+// WriteGolden writes scores as indented JSON, creating parent directories as needed
+func WriteGolden(path string, scores GoldenScores) error {
+	data, err := json.MarshalIndent(scores, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,164 @@
+package goevaltest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// lengthScorer scores outputs by length, capped at 1, so scores are predictable
+type lengthScorer struct{ name string }
+
+func (s lengthScorer) Score(ctx context.Context, in api.ScoreInputs) api.Score {
+	return api.Score{Name: s.name, Score: min(1, float64(len(in.Output))/10), Metadata: map[string]any{}}
+}
+
+func TestRequireScore(t *testing.T) {
+	score := RequireScore(t, lengthScorer{name: "Length"}, api.ScoreInputs{Output: "0123456789"}, 0.9)
+	if score.Score != 1 {
+		t.Errorf("RequireScore() = %v, want 1", score.Score)
+	}
+	AssertScore(t, lengthScorer{name: "Length"}, api.ScoreInputs{Output: "01234"}, 0.5)
+}
+
+func TestCheckScore(t *testing.T) {
+	tests := []struct {
+		name  string
+		score api.Score
+		min   float64
+		want  string
+	}{
+		{name: "pass", score: api.Score{Name: "S", Score: 0.8}, min: 0.8, want: ""},
+		{name: "below", score: api.Score{Name: "S", Score: 0.5}, min: 0.8, want: "S: score 0.5000 is below 0.8000"},
+		{
+			name:  "below with explanation",
+			score: api.Score{Name: "S", Score: 0, Metadata: map[string]any{"explanation": "wrong city"}},
+			min:   0.5,
+			want:  "S: score 0.0000 is below 0.5000\nexplanation: wrong city",
+		},
+		{name: "error", score: api.Score{Name: "S", Error: errors.New("boom")}, min: 0, want: "S: error: boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkScore(tt.score, tt.min); got != tt.want {
+				t.Errorf("checkScore() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareGolden(t *testing.T) {
+	golden := map[string]float64{"A": 0.8, "B": 0.5}
+
+	tests := []struct {
+		name   string
+		scores map[string]float64
+		want   []string
+	}{
+		{name: "within tolerance", scores: map[string]float64{"A": 0.76, "B": 0.9}},
+		{name: "regression", scores: map[string]float64{"A": 0.7, "B": 0.5}, want: []string{"A: score regressed from 0.8000 to 0.7000"}},
+		{name: "missing", scores: map[string]float64{"A": 0.8, "B": 0.9, "C": 1}, want: []string{"C: no golden score"}},
+		{name: "not produced", scores: map[string]float64{"A": 0.8}, want: []string{"B: golden score 0.5000 was not produced"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := compareGolden(golden, tt.scores, 0.05)
+			if len(got) != len(tt.want) {
+				t.Fatalf("compareGolden() = %q, want %d messages", got, len(tt.want))
+			}
+			for i := range got {
+				if !strings.HasPrefix(got[i], tt.want[i]) {
+					t.Errorf("message %d = %q, want prefix %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRun_Golden(t *testing.T) {
+	rowMin := 0.9
+	path := filepath.Join(t.TempDir(), "golden", "length.json")
+	rows := []Row{
+		{Name: "short", Inputs: api.ScoreInputs{Output: "abc"}},
+		{Inputs: api.ScoreInputs{Output: "0123456789"}, MinScore: &rowMin},
+	}
+	scorers := []api.Scorer{lengthScorer{name: "Length"}, lengthScorer{name: "Length"}}
+
+	t.Run("update", func(t *testing.T) {
+		t.Setenv("UPDATE_TESTS", "true")
+		Run(t, rows, scorers, Options{Golden: path, Parallel: true})
+	})
+
+	got, err := ReadGolden(path)
+	if err != nil {
+		t.Fatalf("ReadGolden() error = %v", err)
+	}
+	want := GoldenScores{
+		"short": {"Length": 0.3, "Length#2": 0.3},
+		"row_1": {"Length": 1, "Length#2": 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("golden = %v, want %v", got, want)
+	}
+
+	t.Run("check", func(t *testing.T) {
+		Run(t, rows, scorers, Options{Golden: path, MinScore: 0.2})
+	})
+}
+
+func TestReadGolden(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    GoldenScores
+		wantErr string
+	}{
+		{name: "scores", content: `{"a": {"X": 0.5}, "b": {}}`, want: GoldenScores{"a": {"X": 0.5}, "b": {}}},
+		{name: "null", content: `null`, want: GoldenScores{}},
+		{name: "duplicate row", content: `{"a": {"X": 0.5}, "a": {"X": 0.9}}`, wantErr: `duplicate row "a"`},
+		{name: "duplicate scorer", content: `{"a": {"X": 0.5, "X": 0.9}}`, wantErr: `duplicate scorer "X" in row "a"`},
+		{name: "invalid score", content: `{"a": {"X": "high"}}`, wantErr: `row "a", scorer "X"`},
+		{name: "not an object", content: `[]`, wantErr: "expected an object"},
+		{name: "trailing data", content: `{} {}`, wantErr: "unexpected data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "golden.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadGolden(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ReadGolden() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadGolden() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadGolden() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUniqueName(t *testing.T) {
+	scores := map[string]float64{"A": 1, "A#2": 1}
+	if got := uniqueName(scores, "A"); got != "A#3" {
+		t.Errorf("uniqueName() = %q, want A#3", got)
+	}
+	if got := uniqueName(scores, "B"); got != "B" {
+		t.Errorf("uniqueName() = %q, want B", got)
+	}
+}
@@ -0,0 +1,151 @@
+package heuristic
+
+import (
+	"context"
+	"math"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// BLEUSmoothing selects how zero n-gram precisions are handled in sentence-level BLEU
+type BLEUSmoothing int
+
+const (
+	// BLEUSmoothingAddOne adds one to the numerator and denominator of precisions for n > 1 (Lin & Och, 2004)
+	BLEUSmoothingAddOne BLEUSmoothing = iota
+	// BLEUSmoothingEpsilon replaces zero matched counts with a small epsilon (0.1)
+	BLEUSmoothingEpsilon
+	// BLEUSmoothingNone disables smoothing; any zero precision yields a score of 0
+	BLEUSmoothingNone
+)
+
+// BLEUOptions configures the BLEU scorer
+type BLEUOptions struct {
+	// MaxN is the highest n-gram order to use (default: 4)
+	MaxN int
+	// Smoothing selects the smoothing method (default: BLEUSmoothingAddOne)
+	Smoothing BLEUSmoothing
+	// Tokenizer splits text into tokens (default: DefaultTokenizer)
+	Tokenizer Tokenizer
+}
+
+// BLEU returns a scorer that computes sentence-level BLEU between Output and the references
+// All references (Expected and ExpectedAlternatives) are used for clipping n-gram counts,
+// and the brevity penalty uses the reference length closest to the output length
+func BLEU(opts BLEUOptions) api.Scorer {
+	return &bleuScorer{opts: opts}
+}
+
+type bleuScorer struct {
+	opts BLEUOptions
+}
+
+const bleuEpsilon = 0.1
+
+func (s *bleuScorer) Score(ctx context.Context, in api.ScoreInputs) api.Score {
+	result := api.Score{
+		Name:     "BLEU",
+		Metadata: make(map[string]any),
+	}
+
+	references := in.References()
+	if len(references) == 0 {
+		result.Error = api.ErrNoExpectedValue
+		result.Score = 0
+		return result
+	}
+
+	maxN := s.opts.MaxN
+	if maxN <= 0 {
+		maxN = 4
+	}
+	tokenize := s.opts.Tokenizer
+	if tokenize == nil {
+		tokenize = DefaultTokenizer
+	}
+
+	outputTokens := tokenize(in.Output)
+	referenceTokens := make([][]string, len(references))
+	for i, ref := range references {
+		referenceTokens[i] = tokenize(ref)
+	}
+
+	// Modified n-gram precisions, clipped by the maximum count in any reference
+	precisions := make([]float64, maxN)
+	for n := 1; n <= maxN; n++ {
+		outputCounts := ngramCounts(outputTokens, n)
+		maxRefCounts := make(map[string]int)
+		for _, tokens := range referenceTokens {
+			for gram, count := range ngramCounts(tokens, n) {
+				maxRefCounts[gram] = max(maxRefCounts[gram], count)
+			}
+		}
+
+		matched := float64(overlapCount(outputCounts, maxRefCounts))
+		total := float64(max(len(outputTokens)-n+1, 0))
+
+		switch s.opts.Smoothing {
+		case BLEUSmoothingAddOne:
+			if n > 1 {
+				matched++
+				total++
+			}
+		case BLEUSmoothingEpsilon:
+			if matched == 0 && total > 0 {
+				matched = bleuEpsilon
+			}
+		}
+
+		if total > 0 {
+			precisions[n-1] = matched / total
+		}
+	}
+
+	// Brevity penalty against the closest reference length (ties prefer the shorter reference)
+	outputLength := len(outputTokens)
+	referenceLength := len(referenceTokens[0])
+	for _, tokens := range referenceTokens[1:] {
+		diff := abs(len(tokens) - outputLength)
+		bestDiff := abs(referenceLength - outputLength)
+		if diff < bestDiff || (diff == bestDiff && len(tokens) < referenceLength) {
+			referenceLength = len(tokens)
+		}
+	}
+
+	brevityPenalty := 1.0
+	if outputLength == 0 {
+		brevityPenalty = 0
+	} else if outputLength < referenceLength {
+		brevityPenalty = math.Exp(1 - float64(referenceLength)/float64(outputLength))
+	}
+
+	score := 0.0
+	if brevityPenalty > 0 {
+		logSum := 0.0
+		for _, p := range precisions {
+			if p == 0 {
+				logSum = math.Inf(-1)
+				break
+			}
+			logSum += math.Log(p)
+		}
+		score = brevityPenalty * math.Exp(logSum/float64(maxN))
+	}
+
+	result.Score = score
+	result.Metadata["precisions"] = precisions
+	result.Metadata["brevity_penalty"] = brevityPenalty
+	result.Metadata["output_length"] = outputLength
+	result.Metadata["reference_length"] = referenceLength
+	result.Metadata["max_n"] = maxN
+	result.Metadata["references"] = len(references)
+
+	return result
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,108 @@
+package heuristic
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+func TestBLEU(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		opts         BLEUOptions
+		output       string
+		expected     string
+		alternatives []string
+		wantErr      error
+		wantScore    float64
+	}{
+		{
+			name:      "identical",
+			opts:      BLEUOptions{},
+			output:    "the cat sat on the mat",
+			expected:  "the cat sat on the mat",
+			wantScore: 1.0,
+		},
+		{
+			name:      "partial overlap with add-one smoothing",
+			opts:      BLEUOptions{},
+			output:    "the cat sat on the mat",
+			expected:  "the cat is on the mat",
+			wantScore: 0.4855,
+		},
+		{
+			name:      "partial overlap without smoothing",
+			opts:      BLEUOptions{Smoothing: BLEUSmoothingNone},
+			output:    "the cat sat on the mat",
+			expected:  "the cat is on the mat",
+			wantScore: 0.0,
+		},
+		{
+			name:      "partial overlap bigram BLEU without smoothing",
+			opts:      BLEUOptions{MaxN: 2, Smoothing: BLEUSmoothingNone},
+			output:    "the cat sat on the mat",
+			expected:  "the cat is on the mat",
+			wantScore: math.Sqrt(5.0 / 6.0 * 3.0 / 5.0),
+		},
+		{
+			name:      "no overlap",
+			opts:      BLEUOptions{},
+			output:    "completely unrelated words",
+			expected:  "the cat sat on the mat",
+			wantScore: 0.0,
+		},
+		{
+			name:      "brevity penalty",
+			opts:      BLEUOptions{MaxN: 1},
+			output:    "the cat",
+			expected:  "the cat sat on the mat",
+			wantScore: math.Exp(1 - 6.0/2.0),
+		},
+		{
+			name:         "best matching alternative reference",
+			opts:         BLEUOptions{},
+			output:       "the cat sat on the mat",
+			expected:     "a dog lay on a rug",
+			alternatives: []string{"the cat sat on the mat"},
+			wantScore:    1.0,
+		},
+		{
+			name:      "whitespace tokenizer is case sensitive",
+			opts:      BLEUOptions{MaxN: 1, Tokenizer: WhitespaceTokenizer},
+			output:    "Paris",
+			expected:  "paris",
+			wantScore: 0.0,
+		},
+		{
+			name:      "no expected value",
+			opts:      BLEUOptions{},
+			output:    "the cat",
+			expected:  "",
+			wantErr:   api.ErrNoExpectedValue,
+			wantScore: 0.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scorer := BLEU(tt.opts)
+			result := scorer.Score(ctx, api.ScoreInputs{Output: tt.output, Expected: tt.expected, ExpectedAlternatives: tt.alternatives})
+
+			if result.Error != tt.wantErr {
+				t.Errorf("BLEU.Score() error = %v, wantErr %v", result.Error, tt.wantErr)
+			}
+
+			if math.Abs(result.Score-tt.wantScore) > 0.001 {
+				t.Errorf("BLEU.Score() score = %v, wantScore %v", result.Score, tt.wantScore)
+			}
+
+			if result.Name != "BLEU" {
+				t.Errorf("BLEU.Score() name = %v, want 'BLEU'", result.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package heuristic
+
+import (
+	"context"
+	"unicode"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// ChrFOptions configures the ChrF scorer
+type ChrFOptions struct {
+	// MaxN is the highest character n-gram order to use (default: 6)
+	MaxN int
+	// Beta weights recall over precision (default: 2)
+	Beta float64
+	// IncludeWhitespace keeps whitespace characters when building n-grams (default: whitespace is removed)
+	IncludeWhitespace bool
+}
+
+// ChrF returns a scorer that computes the character n-gram F-score (chrF) between Output and the references
+// Precision and recall are averaged over n-gram orders before combining them into the F-score
+// With multiple references, the reference with the highest score is used
+func ChrF(opts ChrFOptions) api.Scorer {
+	return &chrfScorer{opts: opts}
+}
+
+type chrfScorer struct {
+	opts ChrFOptions
+}
+
+func (s *chrfScorer) Score(ctx context.Context, in api.ScoreInputs) api.Score {
+	result := api.Score{
+		Name:     "ChrF",
+		Metadata: make(map[string]any),
+	}
+
+	references := in.References()
+	if len(references) == 0 {
+		result.Error = api.ErrNoExpectedValue
+		result.Score = 0
+		return result
+	}
+
+	maxN := s.opts.MaxN
+	if maxN <= 0 {
+		maxN = 6
+	}
+	beta := s.opts.Beta
+	if beta <= 0 {
+		beta = 2
+	}
+
+	outputChars := s.characters(in.Output)
+
+	bestIndex := -1
+	var bestPrecision, bestRecall, bestScore float64
+	for i, ref := range references {
+		referenceChars := s.characters(ref)
+
+		var precisionSum, recallSum float64
+		orders := 0
+		for n := 1; n <= maxN; n++ {
+			outputTotal := max(len(outputChars)-n+1, 0)
+			referenceTotal := max(len(referenceChars)-n+1, 0)
+			if outputTotal == 0 && referenceTotal == 0 {
+				continue
+			}
+			orders++
+			matched := float64(overlapCount(ngramCounts(outputChars, n), ngramCounts(referenceChars, n)))
+			if outputTotal > 0 {
+				precisionSum += matched / float64(outputTotal)
+			}
+			if referenceTotal > 0 {
+				recallSum += matched / float64(referenceTotal)
+			}
+		}
+
+		var precision, recall float64
+		if orders > 0 {
+			precision = precisionSum / float64(orders)
+			recall = recallSum / float64(orders)
+		}
+		score := fScore(precision, recall, beta)
+
+		if bestIndex < 0 || score > bestScore {
+			bestIndex = i
+			bestPrecision, bestRecall, bestScore = precision, recall, score
+		}
+	}
+
+	result.Score = bestScore
+	result.Metadata["precision"] = bestPrecision
+	result.Metadata["recall"] = bestRecall
+	result.Metadata["max_n"] = maxN
+	result.Metadata["beta"] = beta
+	result.Metadata["reference_index"] = bestIndex
+
+	return result
+}
+
+// characters splits text into single-character tokens, dropping whitespace unless configured otherwise
+func (s *chrfScorer) characters(text string) []string {
+	chars := make([]string, 0, len(text))
+	for _, r := range text {
+		if !s.opts.IncludeWhitespace && unicode.IsSpace(r) {
+			continue
+		}
+		chars = append(chars, string(r))
+	}
+	return chars
+}
@@ -0,0 +1,101 @@
+package heuristic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+func TestChrF(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		opts         ChrFOptions
+		output       string
+		expected     string
+		alternatives []string
+		wantErr      error
+		wantMinScore float64
+		wantMaxScore float64
+	}{
+		{
+			name:         "identical",
+			opts:         ChrFOptions{},
+			output:       "The cat sat on the mat.",
+			expected:     "The cat sat on the mat.",
+			wantMinScore: 1.0,
+			wantMaxScore: 1.0,
+		},
+		{
+			name:         "whitespace ignored by default",
+			opts:         ChrFOptions{},
+			output:       "thecat  sat",
+			expected:     "the cat sat",
+			wantMinScore: 1.0,
+			wantMaxScore: 1.0,
+		},
+		{
+			name:         "whitespace included",
+			opts:         ChrFOptions{IncludeWhitespace: true},
+			output:       "thecat  sat",
+			expected:     "the cat sat",
+			wantMinScore: 0.3,
+			wantMaxScore: 0.9,
+		},
+		{
+			name:         "morphological variant scores high",
+			opts:         ChrFOptions{},
+			output:       "the cats are sitting",
+			expected:     "the cat is sitting",
+			wantMinScore: 0.6,
+			wantMaxScore: 0.9,
+		},
+		{
+			name:         "no character overlap",
+			opts:         ChrFOptions{},
+			output:       "xyz",
+			expected:     "abc",
+			wantMinScore: 0.0,
+			wantMaxScore: 0.0,
+		},
+		{
+			name:         "best of multiple references",
+			opts:         ChrFOptions{},
+			output:       "bonjour",
+			expected:     "hello",
+			alternatives: []string{"bonjour"},
+			wantMinScore: 1.0,
+			wantMaxScore: 1.0,
+		},
+		{
+			name:         "no expected value",
+			opts:         ChrFOptions{},
+			output:       "abc",
+			expected:     "",
+			wantErr:      api.ErrNoExpectedValue,
+			wantMinScore: 0.0,
+			wantMaxScore: 0.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scorer := ChrF(tt.opts)
+			result := scorer.Score(ctx, api.ScoreInputs{Output: tt.output, Expected: tt.expected, ExpectedAlternatives: tt.alternatives})
+
+			if result.Error != tt.wantErr {
+				t.Errorf("ChrF.Score() error = %v, wantErr %v", result.Error, tt.wantErr)
+			}
+
+			if result.Score < tt.wantMinScore-1e-9 || result.Score > tt.wantMaxScore+1e-9 {
+				t.Errorf("ChrF.Score() score = %v, want between %v and %v", result.Score, tt.wantMinScore, tt.wantMaxScore)
+			}
+
+			if result.Name != "ChrF" {
+				t.Errorf("ChrF.Score() name = %v, want 'ChrF'", result.Name)
+			}
+		})
+	}
+}
@@ -13,9 +13,12 @@ type ExactMatchOptions struct {
 	CaseInsensitive bool
 	// TrimWhitespace determines if leading and trailing whitespace should be trimmed
 	TrimWhitespace bool
+	// Aggregation combines results across Expected and ExpectedAlternatives (default: api.AggregateBest)
+	Aggregation api.ReferenceAggregation
 }
 
 // ExactMatch returns a scorer that checks if the output exactly matches the expected value
+// When ExpectedAlternatives are given, the output is compared against every reference
 func ExactMatch(opts ExactMatchOptions) api.Scorer {
 	return &exactMatchScorer{opts: opts}
 }
@@ -30,35 +33,45 @@ func (s *exactMatchScorer) Score(ctx context.Context, in api.ScoreInputs) api.Sc
 		Metadata: make(map[string]any),
 	}
 
-	if in.Expected == "" {
+	references := in.References()
+	if len(references) == 0 {
 		result.Error = api.ErrNoExpectedValue
 		result.Score = 0
 		return result
 	}
 
-	outputToCompare := in.Output
-	expectedToCompare := in.Expected
+	outputToCompare := s.normalize(in.Output)
 
-	if s.opts.TrimWhitespace {
-		outputToCompare = strings.TrimSpace(outputToCompare)
-		expectedToCompare = strings.TrimSpace(expectedToCompare)
-	}
-
-	if s.opts.CaseInsensitive {
-		outputToCompare = strings.ToLower(outputToCompare)
-		expectedToCompare = strings.ToLower(expectedToCompare)
+	scores := make([]float64, len(references))
+	for i, ref := range references {
+		if outputToCompare == s.normalize(ref) {
+			scores[i] = 1.0
+		} else {
+			scores[i] = 0.0
+		}
 	}
 
-	if outputToCompare == expectedToCompare {
-		result.Score = 1.0
-	} else {
-		result.Score = 0.0
-	}
+	score, index := s.opts.Aggregation.Aggregate(scores)
+	result.Score = score
 
 	result.Metadata["case_insensitive"] = s.opts.CaseInsensitive
 	result.Metadata["trim_whitespace"] = s.opts.TrimWhitespace
 	result.Metadata["output_length"] = len(in.Output)
-	result.Metadata["expected_length"] = len(in.Expected)
+	result.Metadata["expected_length"] = len(references[index])
+	result.Metadata["reference_index"] = index
+	if len(references) > 1 {
+		result.Metadata["reference_scores"] = scores
+	}
 
 	return result
 }
+
+func (s *exactMatchScorer) normalize(text string) string {
+	if s.opts.TrimWhitespace {
+		text = strings.TrimSpace(text)
+	}
+	if s.opts.CaseInsensitive {
+		text = strings.ToLower(text)
+	}
+	return text
+}
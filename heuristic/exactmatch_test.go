@@ -110,3 +110,89 @@ func TestExactMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestExactMatch_MultipleReferences(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		opts         ExactMatchOptions
+		output       string
+		expected     string
+		alternatives []string
+		wantErr      error
+		wantScore    float64
+		wantIndex    int
+	}{
+		{
+			name:         "matches alternative",
+			opts:         ExactMatchOptions{CaseInsensitive: true},
+			output:       "NYC",
+			expected:     "New York City",
+			alternatives: []string{"New York", "nyc"},
+			wantScore:    1.0,
+			wantIndex:    2,
+		},
+		{
+			name:         "matches none",
+			opts:         ExactMatchOptions{},
+			output:       "Boston",
+			expected:     "New York City",
+			alternatives: []string{"NYC"},
+			wantScore:    0.0,
+			wantIndex:    0,
+		},
+		{
+			name:         "mean aggregation",
+			opts:         ExactMatchOptions{Aggregation: api.AggregateMean},
+			output:       "NYC",
+			expected:     "New York City",
+			alternatives: []string{"NYC"},
+			wantScore:    0.5,
+			wantIndex:    1,
+		},
+		{
+			name:         "worst aggregation",
+			opts:         ExactMatchOptions{Aggregation: api.AggregateWorst},
+			output:       "NYC",
+			expected:     "NYC",
+			alternatives: []string{"New York City"},
+			wantScore:    0.0,
+			wantIndex:    1,
+		},
+		{
+			name:         "alternatives only",
+			opts:         ExactMatchOptions{},
+			output:       "NYC",
+			expected:     "",
+			alternatives: []string{"NYC"},
+			wantScore:    1.0,
+			wantIndex:    0,
+		},
+		{
+			name:         "empty alternatives ignored",
+			opts:         ExactMatchOptions{},
+			output:       "NYC",
+			expected:     "",
+			alternatives: []string{""},
+			wantErr:      api.ErrNoExpectedValue,
+			wantScore:    0.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ExactMatch(tt.opts).Score(ctx, api.ScoreInputs{Output: tt.output, Expected: tt.expected, ExpectedAlternatives: tt.alternatives})
+
+			if result.Error != tt.wantErr {
+				t.Fatalf("ExactMatch.Score() error = %v, wantErr %v", result.Error, tt.wantErr)
+			}
+			if result.Score != tt.wantScore {
+				t.Errorf("ExactMatch.Score() score = %v, wantScore %v", result.Score, tt.wantScore)
+			}
+			if tt.wantErr == nil && result.Metadata["reference_index"] != tt.wantIndex {
+				t.Errorf("ExactMatch.Score() reference_index = %v, want %v", result.Metadata["reference_index"], tt.wantIndex)
+			}
+		})
+	}
+}
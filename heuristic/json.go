@@ -0,0 +1,390 @@
+package heuristic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/datar-psa/goeval/api"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// JSONValidOptions configures the JSONValid scorer
+type JSONValidOptions struct {
+	// Additional configuration options can be added here
+}
+
+// JSONValid returns a scorer that checks if the output parses as JSON
+// Returns 1.0 for valid JSON, 0.0 otherwise
+func JSONValid(opts JSONValidOptions) api.Scorer {
+	return &jsonValidScorer{opts: opts}
+}
+
+type jsonValidScorer struct {
+	opts JSONValidOptions
+}
+
+func (s *jsonValidScorer) Score(ctx context.Context, in api.ScoreInputs) api.Score {
+	result := api.Score{
+		Name:     "JSONValid",
+		Metadata: make(map[string]any),
+	}
+
+	value, err := parseJSON(in.Output)
+	if err != nil {
+		result.Score = 0
+		result.Metadata["parse_error"] = err.Error()
+		return result
+	}
+
+	result.Score = 1.0
+	result.Metadata["type"] = jsonTypeName(value)
+
+	return result
+}
+
+// JSONSchemaOptions configures the JSONSchema scorer
+type JSONSchemaOptions struct {
+	// Schema is the JSON Schema the output must satisfy (required)
+	Schema map[string]any
+}
+
+// JSONSchema returns a scorer that validates the output against a JSON Schema
+// Returns 1.0 if the output is valid JSON satisfying the schema, 0.0 otherwise
+// Schema violations are reported in metadata as "violations"
+func JSONSchema(opts JSONSchemaOptions) api.Scorer {
+	return &jsonSchemaScorer{opts: opts}
+}
+
+type jsonSchemaScorer struct {
+	opts JSONSchemaOptions
+
+	once   sync.Once
+	schema *jsonschema.Schema
+	err    error
+}
+
+func (s *jsonSchemaScorer) Score(ctx context.Context, in api.ScoreInputs) api.Score {
+	result := api.Score{
+		Name:     "JSONSchema",
+		Metadata: make(map[string]any),
+	}
+
+	s.once.Do(s.compile)
+	if s.err != nil {
+		result.Error = s.err
+		result.Score = 0
+		return result
+	}
+
+	value, err := parseJSON(in.Output)
+	if err != nil {
+		result.Score = 0
+		result.Metadata["parse_error"] = err.Error()
+		return result
+	}
+
+	violations := []string{}
+	if err := s.schema.Validate(value); err != nil {
+		validationErr, ok := err.(*jsonschema.ValidationError)
+		if !ok {
+			result.Error = fmt.Errorf("failed to validate output: %w", err)
+			result.Score = 0
+			return result
+		}
+		violations = schemaViolations(validationErr)
+	}
+
+	if len(violations) == 0 {
+		result.Score = 1.0
+	} else {
+		result.Score = 0.0
+	}
+	result.Metadata["violations"] = violations
+
+	return result
+}
+
+// compile compiles the configured schema once per scorer
+func (s *jsonSchemaScorer) compile() {
+	if s.opts.Schema == nil {
+		s.err = fmt.Errorf("JSON schema is required")
+		return
+	}
+
+	// Round-trip through the library decoder so numbers are represented the way it expects
+	raw, err := json.Marshal(s.opts.Schema)
+	if err != nil {
+		s.err = fmt.Errorf("failed to marshal schema: %w", err)
+		return
+	}
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(string(raw)))
+	if err != nil {
+		s.err = fmt.Errorf("failed to unmarshal schema: %w", err)
+		return
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("schema.json", doc); err != nil {
+		s.err = fmt.Errorf("failed to add schema: %w", err)
+		return
+	}
+	schema, err := compiler.Compile("schema.json")
+	if err != nil {
+		s.err = fmt.Errorf("failed to compile schema: %w", err)
+		return
+	}
+	s.schema = schema
+}
+
+// schemaViolations flattens a validation error into "path: message" strings for its leaf causes
+func schemaViolations(err *jsonschema.ValidationError) []string {
+	var violations []string
+	var walk func(unit jsonschema.OutputUnit)
+	walk = func(unit jsonschema.OutputUnit) {
+		if len(unit.Errors) == 0 {
+			if unit.Error != nil {
+				violations = append(violations, fmt.Sprintf("%s: %s", jsonPath(unit.InstanceLocation), unit.Error))
+			}
+			return
+		}
+		for _, child := range unit.Errors {
+			walk(child)
+		}
+	}
+	walk(*err.DetailedOutput())
+	return violations
+}
+
+// JSONDiffOptions configures the JSONDiff scorer
+type JSONDiffOptions struct {
+	// NumericTolerance is the maximum absolute difference for numbers to be considered equal (default: 0, exact)
+	NumericTolerance float64
+	// IgnoreExtraFields does not penalize object fields present in the output but not in the expected value
+	IgnoreExtraFields bool
+}
+
+// JSONDiff returns a scorer that structurally compares the output JSON to the expected JSON
+// Object key order is ignored and array elements are compared by position
+// The score is the fraction of leaf values that match; differing paths are reported in metadata
+func JSONDiff(opts JSONDiffOptions) api.Scorer {
+	return &jsonDiffScorer{opts: opts}
+}
+
+type jsonDiffScorer struct {
+	opts JSONDiffOptions
+}
+
+func (s *jsonDiffScorer) Score(ctx context.Context, in api.ScoreInputs) api.Score {
+	result := api.Score{
+		Name:     "JSONDiff",
+		Metadata: make(map[string]any),
+	}
+
+	if in.Expected == "" {
+		result.Error = api.ErrNoExpectedValue
+		result.Score = 0
+		return result
+	}
+
+	expected, err := parseJSON(in.Expected)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to parse expected JSON: %w", err)
+		result.Score = 0
+		return result
+	}
+
+	output, err := parseJSON(in.Output)
+	if err != nil {
+		result.Score = 0
+		result.Metadata["parse_error"] = err.Error()
+		return result
+	}
+
+	d := &jsonDiff{opts: s.opts, mismatched: []string{}, missing: []string{}, extra: []string{}}
+	d.compare("$", expected, output)
+
+	if d.total == 0 {
+		result.Score = 1.0
+	} else {
+		result.Score = float64(d.matched) / float64(d.total)
+	}
+	result.Metadata["matched"] = d.matched
+	result.Metadata["total"] = d.total
+	result.Metadata["mismatched_paths"] = d.mismatched
+	result.Metadata["missing_paths"] = d.missing
+	result.Metadata["extra_paths"] = d.extra
+	result.Metadata["numeric_tolerance"] = s.opts.NumericTolerance
+
+	return result
+}
+
+// jsonDiff accumulates leaf-level comparison results between two JSON values
+type jsonDiff struct {
+	opts JSONDiffOptions
+
+	matched    int
+	total      int
+	mismatched []string
+	missing    []string
+	extra      []string
+}
+
+func (d *jsonDiff) compare(path string, expected, output any) {
+	switch exp := expected.(type) {
+	case map[string]any:
+		out, ok := output.(map[string]any)
+		if !ok || len(exp) == 0 {
+			d.compareLeaf(path, expected, output)
+			return
+		}
+		for _, key := range sortedKeys(exp) {
+			childPath := path + "." + key
+			if outValue, ok := out[key]; ok {
+				d.compare(childPath, exp[key], outValue)
+			} else {
+				d.total += leafCount(exp[key])
+				d.missing = append(d.missing, childPath)
+			}
+		}
+		if !d.opts.IgnoreExtraFields {
+			for _, key := range sortedKeys(out) {
+				if _, ok := exp[key]; !ok {
+					d.total += leafCount(out[key])
+					d.extra = append(d.extra, path+"."+key)
+				}
+			}
+		}
+	case []any:
+		out, ok := output.([]any)
+		if !ok || len(exp) == 0 {
+			d.compareLeaf(path, expected, output)
+			return
+		}
+		for i, expValue := range exp {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			if i < len(out) {
+				d.compare(childPath, expValue, out[i])
+			} else {
+				d.total += leafCount(expValue)
+				d.missing = append(d.missing, childPath)
+			}
+		}
+		for i := len(exp); i < len(out); i++ {
+			d.total += leafCount(out[i])
+			d.extra = append(d.extra, fmt.Sprintf("%s[%d]", path, i))
+		}
+	default:
+		d.compareLeaf(path, expected, output)
+	}
+}
+
+// compareLeaf compares scalars (and empty or type-mismatched containers) as a whole
+func (d *jsonDiff) compareLeaf(path string, expected, output any) {
+	d.total += leafCount(expected)
+	if d.equal(expected, output) {
+		d.matched += leafCount(expected)
+		return
+	}
+	d.mismatched = append(d.mismatched, path)
+}
+
+func (d *jsonDiff) equal(expected, output any) bool {
+	expNum, expIsNum := expected.(float64)
+	outNum, outIsNum := output.(float64)
+	if expIsNum && outIsNum {
+		return math.Abs(expNum-outNum) <= d.opts.NumericTolerance
+	}
+	switch exp := expected.(type) {
+	case map[string]any:
+		out, ok := output.(map[string]any)
+		return ok && len(exp) == 0 && len(out) == 0
+	case []any:
+		out, ok := output.([]any)
+		return ok && len(exp) == 0 && len(out) == 0
+	default:
+		return expected == output
+	}
+}
+
+// leafCount returns the number of scalar leaves in a JSON value; empty containers count as one leaf
+func leafCount(value any) int {
+	count := 0
+	switch v := value.(type) {
+	case map[string]any:
+		for _, child := range v {
+			count += leafCount(child)
+		}
+	case []any:
+		for _, child := range v {
+			count += leafCount(child)
+		}
+	default:
+		return 1
+	}
+	return max(count, 1)
+}
+
+// parseJSON decodes a single JSON value, rejecting trailing data
+func parseJSON(text string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	// More reports false before a closing bracket, so read on and require the end of input
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after top-level JSON value")
+	}
+	return value, nil
+}
+
+// jsonPath renders a JSON Pointer (e.g. /items/0/name) as a JSONPath-like string (e.g. $.items[0].name)
+func jsonPath(pointer string) string {
+	var sb strings.Builder
+	sb.WriteString("$")
+	if pointer == "" {
+		return sb.String()
+	}
+	for _, tok := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		tok = strings.NewReplacer("~1", "/", "~0", "~").Replace(tok)
+		if _, err := strconv.Atoi(tok); err == nil {
+			sb.WriteString("[" + tok + "]")
+		} else {
+			sb.WriteString("." + tok)
+		}
+	}
+	return sb.String()
+}
+
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return "null"
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,250 @@
+package heuristic
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+func TestJSONValid(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		output    string
+		wantScore float64
+		wantType  string
+	}{
+		{name: "object", output: `{"name": "refund", "args": {"id": 7}}`, wantScore: 1.0, wantType: "object"},
+		{name: "array", output: ` [1, 2, 3] `, wantScore: 1.0, wantType: "array"},
+		{name: "scalar", output: `"hello"`, wantScore: 1.0, wantType: "string"},
+		{name: "truncated", output: `{"name": "refund"`, wantScore: 0.0},
+		{name: "trailing data", output: `{"a": 1} {"b": 2}`, wantScore: 0.0},
+		{name: "trailing bracket after object", output: `{"a":1}]`, wantScore: 0.0},
+		{name: "trailing brace after array", output: `[1]}`, wantScore: 0.0},
+		{name: "trailing whitespace", output: "{\"a\": 1}\n", wantScore: 1.0, wantType: "object"},
+		{name: "plain text", output: `Sure! Here is the JSON`, wantScore: 0.0},
+		{name: "empty", output: ``, wantScore: 0.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := JSONValid(JSONValidOptions{}).Score(ctx, api.ScoreInputs{Output: tt.output})
+
+			if result.Error != nil {
+				t.Errorf("JSONValid.Score() unexpected error = %v", result.Error)
+			}
+			if result.Score != tt.wantScore {
+				t.Errorf("JSONValid.Score() score = %v, wantScore %v", result.Score, tt.wantScore)
+			}
+			if tt.wantType != "" && result.Metadata["type"] != tt.wantType {
+				t.Errorf("JSONValid.Score() type = %v, want %v", result.Metadata["type"], tt.wantType)
+			}
+			if tt.wantScore == 0 && result.Metadata["parse_error"] == nil {
+				t.Error("JSONValid.Score() missing parse_error in metadata")
+			}
+			if result.Name != "JSONValid" {
+				t.Errorf("JSONValid.Score() name = %v, want 'JSONValid'", result.Name)
+			}
+		})
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	ctx := context.Background()
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string", "enum": []string{"refund", "cancel"}},
+			"args": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"order_id": map[string]any{"type": "integer", "minimum": 1},
+				},
+				"required": []string{"order_id"},
+			},
+		},
+		"required":             []string{"name", "args"},
+		"additionalProperties": false,
+	}
+
+	tests := []struct {
+		name           string
+		schema         map[string]any
+		output         string
+		wantErr        bool
+		wantScore      float64
+		wantViolations int
+	}{
+		{
+			name:      "valid",
+			schema:    schema,
+			output:    `{"name": "refund", "args": {"order_id": 42}}`,
+			wantScore: 1.0,
+		},
+		{
+			name:           "missing nested required field",
+			schema:         schema,
+			output:         `{"name": "refund", "args": {}}`,
+			wantScore:      0.0,
+			wantViolations: 1,
+		},
+		{
+			name:           "multiple violations",
+			schema:         schema,
+			output:         `{"name": "delete", "args": {"order_id": 0}, "extra": true}`,
+			wantScore:      0.0,
+			wantViolations: 3,
+		},
+		{
+			name:      "invalid JSON",
+			schema:    schema,
+			output:    `not json`,
+			wantScore: 0.0,
+		},
+		{
+			name:    "missing schema",
+			schema:  nil,
+			output:  `{}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid schema",
+			schema:  map[string]any{"type": 12},
+			output:  `{}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := JSONSchema(JSONSchemaOptions{Schema: tt.schema}).Score(ctx, api.ScoreInputs{Output: tt.output})
+
+			if (result.Error != nil) != tt.wantErr {
+				t.Fatalf("JSONSchema.Score() error = %v, wantErr %v", result.Error, tt.wantErr)
+			}
+			if result.Score != tt.wantScore {
+				t.Errorf("JSONSchema.Score() score = %v, wantScore %v", result.Score, tt.wantScore)
+			}
+			if violations, ok := result.Metadata["violations"].([]string); ok && len(violations) != tt.wantViolations {
+				t.Errorf("JSONSchema.Score() violations = %v, want %d", violations, tt.wantViolations)
+			}
+			if result.Name != "JSONSchema" {
+				t.Errorf("JSONSchema.Score() name = %v, want 'JSONSchema'", result.Name)
+			}
+		})
+	}
+}
+
+func TestJSONDiff(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name           string
+		opts           JSONDiffOptions
+		output         string
+		expected       string
+		wantErr        error
+		wantScore      float64
+		wantMismatched []string
+		wantMissing    []string
+		wantExtra      []string
+	}{
+		{
+			name:      "identical ignoring key order",
+			output:    `{"b": [1, 2], "a": {"x": "y"}}`,
+			expected:  `{"a": {"x": "y"}, "b": [1, 2]}`,
+			wantScore: 1.0,
+		},
+		{
+			name:           "one mismatched leaf",
+			output:         `{"name": "refund", "args": {"order_id": 41, "reason": "late"}}`,
+			expected:       `{"name": "refund", "args": {"order_id": 42, "reason": "late"}}`,
+			wantScore:      2.0 / 3.0,
+			wantMismatched: []string{"$.args.order_id"},
+		},
+		{
+			name:      "numeric tolerance",
+			opts:      JSONDiffOptions{NumericTolerance: 0.01},
+			output:    `{"price": 9.995}`,
+			expected:  `{"price": 10}`,
+			wantScore: 1.0,
+		},
+		{
+			name:        "missing and extra fields",
+			output:      `{"a": 1, "c": 3}`,
+			expected:    `{"a": 1, "b": 2}`,
+			wantScore:   1.0 / 3.0,
+			wantMissing: []string{"$.b"},
+			wantExtra:   []string{"$.c"},
+		},
+		{
+			name:        "ignore extra fields",
+			opts:        JSONDiffOptions{IgnoreExtraFields: true},
+			output:      `{"a": 1, "c": 3}`,
+			expected:    `{"a": 1, "b": 2}`,
+			wantScore:   0.5,
+			wantMissing: []string{"$.b"},
+		},
+		{
+			name:        "array length differs",
+			output:      `{"items": [{"id": 1}]}`,
+			expected:    `{"items": [{"id": 1}, {"id": 2}]}`,
+			wantScore:   0.5,
+			wantMissing: []string{"$.items[1]"},
+		},
+		{
+			name:           "type mismatch",
+			output:         `{"args": "order 42"}`,
+			expected:       `{"args": {"order_id": 42, "reason": "late"}}`,
+			wantScore:      0.0,
+			wantMismatched: []string{"$.args"},
+		},
+		{
+			name:      "invalid output JSON",
+			output:    `{"a": `,
+			expected:  `{"a": 1}`,
+			wantScore: 0.0,
+		},
+		{
+			name:      "no expected value",
+			output:    `{}`,
+			expected:  ``,
+			wantErr:   api.ErrNoExpectedValue,
+			wantScore: 0.0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := JSONDiff(tt.opts).Score(ctx, api.ScoreInputs{Output: tt.output, Expected: tt.expected})
+
+			if result.Error != tt.wantErr {
+				t.Fatalf("JSONDiff.Score() error = %v, wantErr %v", result.Error, tt.wantErr)
+			}
+			if result.Score < tt.wantScore-1e-9 || result.Score > tt.wantScore+1e-9 {
+				t.Errorf("JSONDiff.Score() score = %v, wantScore %v", result.Score, tt.wantScore)
+			}
+			if result.Name != "JSONDiff" {
+				t.Errorf("JSONDiff.Score() name = %v, want 'JSONDiff'", result.Name)
+			}
+			if result.Metadata["parse_error"] != nil || result.Error != nil {
+				return
+			}
+
+			for key, want := range map[string][]string{
+				"mismatched_paths": tt.wantMismatched,
+				"missing_paths":    tt.wantMissing,
+				"extra_paths":      tt.wantExtra,
+			} {
+				got, _ := result.Metadata[key].([]string)
+				if len(got) != len(want) || (len(want) > 0 && !reflect.DeepEqual(got, want)) {
+					t.Errorf("JSONDiff.Score() %s = %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package heuristic
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// KeywordCoverageOptions configures the KeywordCoverage scorer
+type KeywordCoverageOptions struct {
+	// Keywords are the terms the output is required to mention (required)
+	Keywords []string
+	// CaseInsensitive determines if the comparison should ignore case
+	CaseInsensitive bool
+	// WholeWord only counts matches on word boundaries, so "refund" does not match "refunded"
+	WholeWord bool
+}
+
+// KeywordCoverage returns a scorer that measures the fraction of required keywords present in Output
+// Matched and missing keywords are reported in metadata
+func KeywordCoverage(opts KeywordCoverageOptions) api.Scorer {
+	return &keywordCoverageScorer{opts: opts}
+}
+
+type keywordCoverageScorer struct {
+	opts KeywordCoverageOptions
+
+	once     sync.Once
+	matchers []matcher
+	err      error
+}
+
+func (s *keywordCoverageScorer) Score(ctx context.Context, in api.ScoreInputs) api.Score {
+	result := api.Score{
+		Name:     "KeywordCoverage",
+		Metadata: make(map[string]any),
+	}
+
+	s.once.Do(func() {
+		if len(s.opts.Keywords) == 0 {
+			s.err = fmt.Errorf("keywords are required")
+			return
+		}
+		s.matchers = termMatchers(s.opts.Keywords, s.opts.CaseInsensitive, s.opts.WholeWord)
+	})
+	if s.err != nil {
+		result.Error = s.err
+		result.Score = 0
+		return result
+	}
+
+	matched := []string{}
+	missing := []string{}
+	for i, re := range s.matchers {
+		if len(re.findAll(in.Output, 1)) > 0 {
+			matched = append(matched, s.opts.Keywords[i])
+		} else {
+			missing = append(missing, s.opts.Keywords[i])
+		}
+	}
+
+	result.Score = float64(len(matched)) / float64(len(s.opts.Keywords))
+	result.Metadata["matched"] = matched
+	result.Metadata["missing"] = missing
+	result.Metadata["case_insensitive"] = s.opts.CaseInsensitive
+	result.Metadata["whole_word"] = s.opts.WholeWord
+
+	return result
+}
+
+// ForbiddenTermsOptions configures the ForbiddenTerms scorer
+type ForbiddenTermsOptions struct {
+	// Terms are literal strings that must not appear in the output
+	Terms []string
+	// Patterns are regular expressions (RE2 syntax) that must not match the output, e.g. `TICKET-\d+`
+	Patterns []string
+	// CaseInsensitive determines if the comparison should ignore case
+	CaseInsensitive bool
+	// WholeWord only counts literal terms on word boundaries
+	WholeWord bool
+}
+
+// ForbiddenTerms returns a scorer that checks Output does not contain any forbidden term or pattern
+// Returns 1.0 if none are found, 0.0 otherwise; the offending terms are reported in metadata
+func ForbiddenTerms(opts ForbiddenTermsOptions) api.Scorer {
+	return &forbiddenTermsScorer{opts: opts}
+}
+
+type forbiddenTermsScorer struct {
+	opts ForbiddenTermsOptions
+
+	once     sync.Once
+	matchers []matcher
+	labels   []string
+	err      error
+}
+
+func (s *forbiddenTermsScorer) Score(ctx context.Context, in api.ScoreInputs) api.Score {
+	result := api.Score{
+		Name:     "ForbiddenTerms",
+		Metadata: make(map[string]any),
+	}
+
+	s.once.Do(s.compile)
+	if s.err != nil {
+		result.Error = s.err
+		result.Score = 0
+		return result
+	}
+
+	found := []string{}
+	occurrences := map[string][]string{}
+	for i, re := range s.matchers {
+		if matches := re.findAll(in.Output, -1); len(matches) > 0 {
+			found = append(found, s.labels[i])
+			occurrences[s.labels[i]] = matches
+		}
+	}
+
+	if len(found) == 0 {
+		result.Score = 1.0
+	} else {
+		result.Score = 0.0
+	}
+	result.Metadata["found"] = found
+	result.Metadata["occurrences"] = occurrences
+
+	return result
+}
+
+func (s *forbiddenTermsScorer) compile() {
+	if len(s.opts.Terms) == 0 && len(s.opts.Patterns) == 0 {
+		s.err = fmt.Errorf("forbidden terms or patterns are required")
+		return
+	}
+
+	s.matchers = termMatchers(s.opts.Terms, s.opts.CaseInsensitive, s.opts.WholeWord)
+	s.labels = append(s.labels, s.opts.Terms...)
+
+	for _, pattern := range s.opts.Patterns {
+		expr := pattern
+		if s.opts.CaseInsensitive {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			s.err = fmt.Errorf("invalid forbidden pattern %q: %w", pattern, err)
+			return
+		}
+		s.matchers = append(s.matchers, matcher{re: re})
+		s.labels = append(s.labels, pattern)
+	}
+}
+
+// matcher finds a term or pattern in text, optionally only on word boundaries
+type matcher struct {
+	re        *regexp.Regexp
+	wholeWord bool
+}
+
+// findAll returns up to n matches (all when n < 0)
+// RE2's \b only knows ASCII word characters, so whole-word matches are checked on runes instead:
+// the characters around a match must not be letters, digits, marks or underscores
+func (m matcher) findAll(text string, n int) []string {
+	if !m.wholeWord {
+		return m.re.FindAllString(text, n)
+	}
+	var matches []string
+	for _, loc := range m.re.FindAllStringIndex(text, -1) {
+		if n >= 0 && len(matches) >= n {
+			break
+		}
+		before, _ := utf8.DecodeLastRuneInString(text[:loc[0]])
+		after, _ := utf8.DecodeRuneInString(text[loc[1]:])
+		if (loc[0] == 0 || !isWordRune(before)) && (loc[1] == len(text) || !isWordRune(after)) {
+			matches = append(matches, text[loc[0]:loc[1]])
+		}
+	}
+	return matches
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// termMatchers builds a literal matcher for each term
+func termMatchers(terms []string, caseInsensitive, wholeWord bool) []matcher {
+	matchers := make([]matcher, len(terms))
+	for i, term := range terms {
+		expr := regexp.QuoteMeta(term)
+		if caseInsensitive {
+			expr = "(?i)" + expr
+		}
+		matchers[i] = matcher{re: regexp.MustCompile(expr), wholeWord: wholeWord}
+	}
+	return matchers
+}
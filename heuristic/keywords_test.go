@@ -0,0 +1,177 @@
+package heuristic
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+func TestKeywordCoverage(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		opts        KeywordCoverageOptions
+		output      string
+		wantErr     bool
+		wantScore   float64
+		wantMissing []string
+	}{
+		{
+			name:        "all keywords present",
+			opts:        KeywordCoverageOptions{Keywords: []string{"refund", "30 days"}},
+			output:      "You can get a refund within 30 days.",
+			wantScore:   1.0,
+			wantMissing: []string{},
+		},
+		{
+			name:        "partial coverage",
+			opts:        KeywordCoverageOptions{Keywords: []string{"refund", "receipt", "30 days", "store credit"}},
+			output:      "You can get a refund within 30 days.",
+			wantScore:   0.5,
+			wantMissing: []string{"receipt", "store credit"},
+		},
+		{
+			name:        "case sensitive by default",
+			opts:        KeywordCoverageOptions{Keywords: []string{"Refund Policy"}},
+			output:      "see our refund policy",
+			wantScore:   0.0,
+			wantMissing: []string{"Refund Policy"},
+		},
+		{
+			name:        "case insensitive",
+			opts:        KeywordCoverageOptions{Keywords: []string{"Refund Policy"}, CaseInsensitive: true},
+			output:      "see our refund policy",
+			wantScore:   1.0,
+			wantMissing: []string{},
+		},
+		{
+			name:        "whole word",
+			opts:        KeywordCoverageOptions{Keywords: []string{"refund"}, WholeWord: true},
+			output:      "Your order was refunded.",
+			wantScore:   0.0,
+			wantMissing: []string{"refund"},
+		},
+		{
+			name:        "whole word terms with symbols",
+			opts:        KeywordCoverageOptions{Keywords: []string{"C++", ".NET", "$5", "C#"}, WholeWord: true},
+			output:      "We port C++ code to .NET for $5. C#9 is next.",
+			wantScore:   0.75,
+			wantMissing: []string{"C#"},
+		},
+		{
+			name:        "whole word non-ASCII",
+			opts:        KeywordCoverageOptions{Keywords: []string{"café", "caf", "naïve", "na"}, WholeWord: true, CaseInsensitive: true},
+			output:      "Un Café noir, pas naïveté.",
+			wantScore:   0.25,
+			wantMissing: []string{"caf", "naïve", "na"},
+		},
+		{
+			name:    "no keywords",
+			opts:    KeywordCoverageOptions{},
+			output:  "anything",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := KeywordCoverage(tt.opts).Score(ctx, api.ScoreInputs{Output: tt.output})
+
+			if (result.Error != nil) != tt.wantErr {
+				t.Fatalf("KeywordCoverage.Score() error = %v, wantErr %v", result.Error, tt.wantErr)
+			}
+			if result.Score != tt.wantScore {
+				t.Errorf("KeywordCoverage.Score() score = %v, wantScore %v", result.Score, tt.wantScore)
+			}
+			if tt.wantMissing != nil && !reflect.DeepEqual(result.Metadata["missing"], tt.wantMissing) {
+				t.Errorf("KeywordCoverage.Score() missing = %v, want %v", result.Metadata["missing"], tt.wantMissing)
+			}
+			if result.Name != "KeywordCoverage" {
+				t.Errorf("KeywordCoverage.Score() name = %v, want 'KeywordCoverage'", result.Name)
+			}
+		})
+	}
+}
+
+func TestForbiddenTerms(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		opts      ForbiddenTermsOptions
+		output    string
+		wantErr   bool
+		wantScore float64
+		wantFound []string
+	}{
+		{
+			name:      "clean output",
+			opts:      ForbiddenTermsOptions{Terms: []string{"internal"}, Patterns: []string{`TICKET-\d+`}},
+			output:    "We have escalated your request.",
+			wantScore: 1.0,
+			wantFound: []string{},
+		},
+		{
+			name:      "forbidden pattern",
+			opts:      ForbiddenTermsOptions{Terms: []string{"internal"}, Patterns: []string{`TICKET-\d+`}},
+			output:    "We have escalated this as TICKET-1234.",
+			wantScore: 0.0,
+			wantFound: []string{`TICKET-\d+`},
+		},
+		{
+			name:      "case insensitive term and pattern",
+			opts:      ForbiddenTermsOptions{Terms: []string{"Internal"}, Patterns: []string{`ticket-\d+`}, CaseInsensitive: true},
+			output:    "per INTERNAL note on TICKET-9",
+			wantScore: 0.0,
+			wantFound: []string{"Internal", `ticket-\d+`},
+		},
+		{
+			name:      "whole word",
+			opts:      ForbiddenTermsOptions{Terms: []string{"ass"}, WholeWord: true},
+			output:    "We will assist you.",
+			wantScore: 1.0,
+			wantFound: []string{},
+		},
+		{
+			name:      "whole word symbols and non-ASCII",
+			opts:      ForbiddenTermsOptions{Terms: []string{"C++", "über"}, WholeWord: true},
+			output:    "Written in C++, not überall.",
+			wantScore: 0.0,
+			wantFound: []string{"C++"},
+		},
+		{
+			name:    "invalid pattern",
+			opts:    ForbiddenTermsOptions{Patterns: []string{`(`}},
+			output:  "anything",
+			wantErr: true,
+		},
+		{
+			name:    "nothing configured",
+			opts:    ForbiddenTermsOptions{},
+			output:  "anything",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ForbiddenTerms(tt.opts).Score(ctx, api.ScoreInputs{Output: tt.output})
+
+			if (result.Error != nil) != tt.wantErr {
+				t.Fatalf("ForbiddenTerms.Score() error = %v, wantErr %v", result.Error, tt.wantErr)
+			}
+			if result.Score != tt.wantScore {
+				t.Errorf("ForbiddenTerms.Score() score = %v, wantScore %v", result.Score, tt.wantScore)
+			}
+			if tt.wantFound != nil && !reflect.DeepEqual(result.Metadata["found"], tt.wantFound) {
+				t.Errorf("ForbiddenTerms.Score() found = %v, want %v", result.Metadata["found"], tt.wantFound)
+			}
+			if result.Name != "ForbiddenTerms" {
+				t.Errorf("ForbiddenTerms.Score() name = %v, want 'ForbiddenTerms'", result.Name)
+			}
+		})
+	}
+}
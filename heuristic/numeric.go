@@ -0,0 +1,230 @@
+package heuristic
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// NumberSelection selects which number is taken from the output when it contains several
+type NumberSelection int
+
+const (
+	// NumberLast uses the last number in the output, where final answers usually appear
+	NumberLast NumberSelection = iota
+	// NumberFirst uses the first number in the output
+	NumberFirst
+	// NumberAny uses whichever number in the output is closest to the expected value
+	NumberAny
+)
+
+// NumericMatchOptions configures the NumericMatch scorer
+type NumericMatchOptions struct {
+	// AbsoluteTolerance is the maximum absolute difference considered a match (default: 0)
+	AbsoluteTolerance float64
+	// RelativeTolerance is the maximum difference relative to the expected value considered a match (default: 0)
+	RelativeTolerance float64
+	// Select chooses which number to take from Output (default: NumberLast)
+	// The first number in each reference is always used as the expected value
+	Select NumberSelection
+	// PercentAsFraction converts percentages to fractions, so "50%" equals "0.5"
+	PercentAsFraction bool
+	// GradedCredit gives partial credit outside the tolerance, decreasing linearly with relative error
+	GradedCredit bool
+	// MaxRelativeError is the relative error at which graded credit reaches 0 (default: 1.0)
+	MaxRelativeError float64
+}
+
+// NumericMatch returns a scorer that extracts numbers from Output and the references and compares them
+// Thousands separators, currency symbols, percentages and scientific notation are understood,
+// so "The total is $1,234.50" matches "1234.5"
+func NumericMatch(opts NumericMatchOptions) api.Scorer {
+	return &numericMatchScorer{opts: opts}
+}
+
+type numericMatchScorer struct {
+	opts NumericMatchOptions
+}
+
+func (s *numericMatchScorer) Score(ctx context.Context, in api.ScoreInputs) api.Score {
+	result := api.Score{
+		Name:     "NumericMatch",
+		Metadata: make(map[string]any),
+	}
+
+	references := in.References()
+	if len(references) == 0 {
+		result.Error = api.ErrNoExpectedValue
+		result.Score = 0
+		return result
+	}
+
+	var expectedNumbers []extractedNumber
+	for _, ref := range references {
+		numbers := extractNumbers(ref, s.opts.PercentAsFraction)
+		if len(numbers) > 0 {
+			expectedNumbers = append(expectedNumbers, numbers[0])
+		}
+	}
+	if len(expectedNumbers) == 0 {
+		result.Error = fmt.Errorf("no number found in expected value")
+		result.Score = 0
+		return result
+	}
+
+	outputNumbers := extractNumbers(in.Output, s.opts.PercentAsFraction)
+	outputValues := make([]float64, len(outputNumbers))
+	for i, n := range outputNumbers {
+		outputValues[i] = n.value
+	}
+	result.Metadata["output_numbers"] = outputValues
+
+	if len(outputNumbers) == 0 {
+		result.Score = 0
+		result.Metadata["expected_number"] = expectedNumbers[0].value
+		result.Metadata["within_tolerance"] = false
+		return result
+	}
+
+	candidates := outputNumbers
+	switch s.opts.Select {
+	case NumberLast:
+		candidates = outputNumbers[len(outputNumbers)-1:]
+	case NumberFirst:
+		candidates = outputNumbers[:1]
+	}
+
+	// Keep the best-scoring pair of output number and expected number
+	var best struct {
+		score    float64
+		within   bool
+		output   extractedNumber
+		expected extractedNumber
+		absErr   float64
+		relErr   float64
+	}
+	best.score = -1
+	for _, out := range candidates {
+		for _, exp := range expectedNumbers {
+			absErr := math.Abs(out.value - exp.value)
+			relErr := relativeError(out.value, exp.value)
+			within := absErr <= s.opts.AbsoluteTolerance || relErr <= s.opts.RelativeTolerance
+
+			score := 0.0
+			if within {
+				score = 1.0
+			} else if s.opts.GradedCredit {
+				maxRel := s.opts.MaxRelativeError
+				if maxRel <= 0 {
+					maxRel = 1.0
+				}
+				score = math.Max(0, 1-relErr/maxRel)
+			}
+
+			if score > best.score || (score == best.score && absErr < best.absErr) {
+				best.score, best.within = score, within
+				best.output, best.expected = out, exp
+				best.absErr, best.relErr = absErr, relErr
+			}
+		}
+	}
+
+	result.Score = best.score
+	result.Metadata["output_number"] = best.output.value
+	result.Metadata["output_text"] = best.output.text
+	result.Metadata["expected_number"] = best.expected.value
+	result.Metadata["expected_text"] = best.expected.text
+	result.Metadata["absolute_error"] = best.absErr
+	result.Metadata["relative_error"] = best.relErr
+	result.Metadata["within_tolerance"] = best.within
+	if best.output.unit != "" {
+		result.Metadata["output_unit"] = best.output.unit
+	}
+	if best.expected.unit != "" {
+		result.Metadata["expected_unit"] = best.expected.unit
+	}
+
+	return result
+}
+
+// relativeError returns |actual-expected| / |expected|, falling back to the absolute error when expected is 0
+func relativeError(actual, expected float64) float64 {
+	diff := math.Abs(actual - expected)
+	if expected == 0 {
+		return diff
+	}
+	return diff / math.Abs(expected)
+}
+
+// extractedNumber is a number found in text together with its source text and unit
+type extractedNumber struct {
+	value float64
+	text  string
+	unit  string
+}
+
+// numberPattern matches optionally signed numbers with currency symbols, thousands separators,
+// decimals, scientific notation and percentages, e.g. "-$1,234.50", "1.5e-3", "12.5 %"
+// A sign must touch the number or its currency symbol, so "10 - 5" reads 5 rather than -5
+var numberPattern = regexp.MustCompile(`([-+−]?)(?:([$€£¥₹])\s?)?([-+−]?)((?:\d{1,3}(?:,\d{3})+|\d+)(?:\.\d+)?|\.\d+)(?:[eE]([-+]?\d+))?(\s?%)?`)
+
+// extractNumbers returns all numbers found in text in order of appearance
+func extractNumbers(text string, percentAsFraction bool) []extractedNumber {
+	var numbers []extractedNumber
+	for _, idx := range numberPattern.FindAllStringSubmatchIndex(text, -1) {
+		group := func(i int) string {
+			if idx[2*i] < 0 {
+				return ""
+			}
+			return text[idx[2*i]:idx[2*i+1]]
+		}
+
+		digits := strings.ReplaceAll(group(4), ",", "")
+		if exponent := group(5); exponent != "" {
+			digits += "e" + exponent
+		}
+		value, err := strconv.ParseFloat(digits, 64)
+		if err != nil {
+			continue
+		}
+
+		// A leading sign right after a letter or digit is a hyphen ("10-20", "COVID-19"), not a minus
+		start := idx[0]
+		leadingSign := group(1)
+		if leadingSign != "" && start > 0 {
+			if r, _ := utf8.DecodeLastRuneInString(text[:start]); unicode.IsLetter(r) || unicode.IsDigit(r) {
+				start += len(leadingSign)
+				leadingSign = ""
+			}
+		}
+		if isMinus(leadingSign) != isMinus(group(3)) {
+			value = -value
+		}
+
+		unit := group(2)
+		if strings.TrimSpace(group(6)) == "%" {
+			unit = "%"
+			if percentAsFraction {
+				value /= 100
+			}
+		}
+
+		numbers = append(numbers, extractedNumber{
+			value: value,
+			text:  strings.TrimSpace(text[start:idx[1]]),
+			unit:  unit,
+		})
+	}
+	return numbers
+}
+
+func isMinus(sign string) bool {
+	return sign == "-" || sign == "−"
+}
@@ -0,0 +1,171 @@
+package heuristic
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+func TestNumericMatch(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		opts      NumericMatchOptions
+		output    string
+		expected  string
+		wantErr   bool
+		wantScore float64
+	}{
+		{
+			name:      "currency with thousands separator",
+			output:    "The total is $1,234.50",
+			expected:  "1234.5",
+			wantScore: 1.0,
+		},
+		{
+			name:      "last number is the answer",
+			output:    "We had 3 items at $4 each, so the answer is 12",
+			expected:  "12",
+			wantScore: 1.0,
+		},
+		{
+			name:      "first number selection",
+			opts:      NumericMatchOptions{Select: NumberFirst},
+			output:    "12, computed from 3 x 4",
+			expected:  "12",
+			wantScore: 1.0,
+		},
+		{
+			name:      "any number selection",
+			opts:      NumericMatchOptions{Select: NumberAny},
+			output:    "Between 10 and 14, most likely 13",
+			expected:  "14",
+			wantScore: 1.0,
+		},
+		{
+			name:      "scientific notation",
+			output:    "approximately 6.022e23 molecules",
+			expected:  "602,200,000,000,000,000,000,000",
+			wantScore: 1.0,
+		},
+		{
+			name:      "negative number",
+			output:    "The change was -$42",
+			expected:  "-42",
+			wantScore: 1.0,
+		},
+		{
+			name:      "hyphen is not a minus sign",
+			output:    "pages 10-20",
+			expected:  "20",
+			wantScore: 1.0,
+		},
+		{
+			name:      "spaced minus in subtraction is not a sign",
+			output:    "10 - 5",
+			expected:  "5",
+			wantScore: 1.0,
+		},
+		{
+			name:      "percent kept as is by default",
+			output:    "Growth was 50%",
+			expected:  "50",
+			wantScore: 1.0,
+		},
+		{
+			name:      "percent as fraction",
+			opts:      NumericMatchOptions{PercentAsFraction: true},
+			output:    "Growth was 50 %",
+			expected:  "0.5",
+			wantScore: 1.0,
+		},
+		{
+			name:      "outside exact tolerance",
+			output:    "3.14",
+			expected:  "3.14159",
+			wantScore: 0.0,
+		},
+		{
+			name:      "within absolute tolerance",
+			opts:      NumericMatchOptions{AbsoluteTolerance: 0.01},
+			output:    "3.14",
+			expected:  "3.14159",
+			wantScore: 1.0,
+		},
+		{
+			name:      "within relative tolerance",
+			opts:      NumericMatchOptions{RelativeTolerance: 0.01},
+			output:    "about 995",
+			expected:  "1000",
+			wantScore: 1.0,
+		},
+		{
+			name:      "graded credit",
+			opts:      NumericMatchOptions{GradedCredit: true, MaxRelativeError: 0.5},
+			output:    "90",
+			expected:  "100",
+			wantScore: 0.8,
+		},
+		{
+			name:      "graded credit bottoms out",
+			opts:      NumericMatchOptions{GradedCredit: true},
+			output:    "300",
+			expected:  "100",
+			wantScore: 0.0,
+		},
+		{
+			name:      "no number in output",
+			output:    "I don't know",
+			expected:  "42",
+			wantScore: 0.0,
+		},
+		{
+			name:     "no number in expected",
+			output:   "42",
+			expected: "forty-two",
+			wantErr:  true,
+		},
+		{
+			name:     "no expected value",
+			output:   "42",
+			expected: "",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NumericMatch(tt.opts).Score(ctx, api.ScoreInputs{Output: tt.output, Expected: tt.expected})
+
+			if (result.Error != nil) != tt.wantErr {
+				t.Fatalf("NumericMatch.Score() error = %v, wantErr %v", result.Error, tt.wantErr)
+			}
+			if math.Abs(result.Score-tt.wantScore) > 1e-9 {
+				t.Errorf("NumericMatch.Score() score = %v, wantScore %v (metadata %v)", result.Score, tt.wantScore, result.Metadata)
+			}
+			if result.Name != "NumericMatch" {
+				t.Errorf("NumericMatch.Score() name = %v, want 'NumericMatch'", result.Name)
+			}
+		})
+	}
+}
+
+func TestNumericMatch_MultipleReferences(t *testing.T) {
+	ctx := context.Background()
+
+	result := NumericMatch(NumericMatchOptions{}).Score(ctx, api.ScoreInputs{
+		Output:               "It costs €20",
+		Expected:             "25",
+		ExpectedAlternatives: []string{"20"},
+	})
+
+	if result.Score != 1.0 {
+		t.Errorf("NumericMatch.Score() score = %v, want 1.0", result.Score)
+	}
+	if unit := result.Metadata["output_unit"]; unit != "€" {
+		t.Errorf("NumericMatch.Score() output_unit = %v, want €", unit)
+	}
+}
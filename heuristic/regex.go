@@ -0,0 +1,80 @@
+package heuristic
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// RegexOptions configures the Regex scorer
+type RegexOptions struct {
+	// Pattern is the regular expression (RE2 syntax) applied to Output (required)
+	Pattern string
+	// MustNotMatch inverts the check: the output passes only if the pattern does not match
+	MustNotMatch bool
+	// CaseInsensitive makes the pattern ignore case
+	CaseInsensitive bool
+}
+
+// Regex returns a scorer that checks Output against a regular expression
+// Returns 1.0 when the output matches (or does not match, with MustNotMatch), 0.0 otherwise
+func Regex(opts RegexOptions) api.Scorer {
+	return &regexScorer{opts: opts}
+}
+
+type regexScorer struct {
+	opts RegexOptions
+
+	once sync.Once
+	re   *regexp.Regexp
+	err  error
+}
+
+func (s *regexScorer) Score(ctx context.Context, in api.ScoreInputs) api.Score {
+	result := api.Score{
+		Name:     "Regex",
+		Metadata: make(map[string]any),
+	}
+
+	s.once.Do(func() {
+		if s.opts.Pattern == "" {
+			s.err = fmt.Errorf("regex pattern is required")
+			return
+		}
+		pattern := s.opts.Pattern
+		if s.opts.CaseInsensitive {
+			pattern = "(?i)" + pattern
+		}
+		s.re, s.err = regexp.Compile(pattern)
+		if s.err != nil {
+			s.err = fmt.Errorf("invalid regex pattern: %w", s.err)
+		}
+	})
+	if s.err != nil {
+		result.Error = s.err
+		result.Score = 0
+		return result
+	}
+
+	matches := s.re.FindAllString(in.Output, -1)
+	matched := len(matches) > 0
+
+	if matched != s.opts.MustNotMatch {
+		result.Score = 1.0
+	} else {
+		result.Score = 0.0
+	}
+
+	if matches == nil {
+		matches = []string{}
+	}
+	result.Metadata["matched"] = matched
+	result.Metadata["matches"] = matches
+	result.Metadata["pattern"] = s.opts.Pattern
+	result.Metadata["must_not_match"] = s.opts.MustNotMatch
+
+	return result
+}
@@ -0,0 +1,79 @@
+package heuristic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+func TestRegex(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		opts      RegexOptions
+		output    string
+		wantErr   bool
+		wantScore float64
+	}{
+		{
+			name:      "match",
+			opts:      RegexOptions{Pattern: `refund within \d+ days`},
+			output:    "You can request a refund within 30 days.",
+			wantScore: 1.0,
+		},
+		{
+			name:      "no match",
+			opts:      RegexOptions{Pattern: `refund within \d+ days`},
+			output:    "Refunds are not available.",
+			wantScore: 0.0,
+		},
+		{
+			name:      "case insensitive match",
+			opts:      RegexOptions{Pattern: `refund`, CaseInsensitive: true},
+			output:    "Refunds are not available.",
+			wantScore: 1.0,
+		},
+		{
+			name:      "must not match passes",
+			opts:      RegexOptions{Pattern: `INC-\d+`, MustNotMatch: true},
+			output:    "We are looking into it.",
+			wantScore: 1.0,
+		},
+		{
+			name:      "must not match fails",
+			opts:      RegexOptions{Pattern: `INC-\d+`, MustNotMatch: true},
+			output:    "See INC-4821 for details.",
+			wantScore: 0.0,
+		},
+		{
+			name:    "invalid pattern",
+			opts:    RegexOptions{Pattern: `(`},
+			output:  "anything",
+			wantErr: true,
+		},
+		{
+			name:    "missing pattern",
+			opts:    RegexOptions{},
+			output:  "anything",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Regex(tt.opts).Score(ctx, api.ScoreInputs{Output: tt.output})
+
+			if (result.Error != nil) != tt.wantErr {
+				t.Fatalf("Regex.Score() error = %v, wantErr %v", result.Error, tt.wantErr)
+			}
+			if result.Score != tt.wantScore {
+				t.Errorf("Regex.Score() score = %v, wantScore %v", result.Score, tt.wantScore)
+			}
+			if result.Name != "Regex" {
+				t.Errorf("Regex.Score() name = %v, want 'Regex'", result.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package heuristic
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// ROUGEVariant selects which ROUGE measure to compute
+type ROUGEVariant string
+
+const (
+	// ROUGE1 measures unigram overlap
+	ROUGE1 ROUGEVariant = "rouge1"
+	// ROUGE2 measures bigram overlap
+	ROUGE2 ROUGEVariant = "rouge2"
+	// ROUGEL measures the longest common subsequence
+	ROUGEL ROUGEVariant = "rougeL"
+)
+
+// ROUGEOptions configures the ROUGE scorer
+type ROUGEOptions struct {
+	// Variant selects the ROUGE measure (default: ROUGEL)
+	Variant ROUGEVariant
+	// Tokenizer splits text into tokens (default: DefaultTokenizer)
+	Tokenizer Tokenizer
+}
+
+// ROUGE returns a scorer that computes ROUGE between Output and the references
+// The score is the F1 measure; precision and recall are reported in metadata
+// With multiple references, the reference with the highest F1 is used
+func ROUGE(opts ROUGEOptions) api.Scorer {
+	return &rougeScorer{opts: opts}
+}
+
+type rougeScorer struct {
+	opts ROUGEOptions
+}
+
+func (s *rougeScorer) Score(ctx context.Context, in api.ScoreInputs) api.Score {
+	result := api.Score{
+		Name:     "ROUGE",
+		Metadata: make(map[string]any),
+	}
+
+	references := in.References()
+	if len(references) == 0 {
+		result.Error = api.ErrNoExpectedValue
+		result.Score = 0
+		return result
+	}
+
+	variant := s.opts.Variant
+	if variant == "" {
+		variant = ROUGEL
+	}
+	tokenize := s.opts.Tokenizer
+	if tokenize == nil {
+		tokenize = DefaultTokenizer
+	}
+
+	outputTokens := tokenize(in.Output)
+
+	bestIndex := -1
+	var bestPrecision, bestRecall, bestF1 float64
+	for i, ref := range references {
+		referenceTokens := tokenize(ref)
+
+		var matched, outputTotal, referenceTotal int
+		switch variant {
+		case ROUGE1, ROUGE2:
+			n := 1
+			if variant == ROUGE2 {
+				n = 2
+			}
+			outputCounts := ngramCounts(outputTokens, n)
+			referenceCounts := ngramCounts(referenceTokens, n)
+			matched = overlapCount(outputCounts, referenceCounts)
+			outputTotal = max(len(outputTokens)-n+1, 0)
+			referenceTotal = max(len(referenceTokens)-n+1, 0)
+		case ROUGEL:
+			matched = lcsLength(outputTokens, referenceTokens)
+			outputTotal = len(outputTokens)
+			referenceTotal = len(referenceTokens)
+		default:
+			result.Error = fmt.Errorf("unsupported ROUGE variant: %q", variant)
+			result.Score = 0
+			return result
+		}
+
+		var precision, recall float64
+		if outputTotal > 0 {
+			precision = float64(matched) / float64(outputTotal)
+		}
+		if referenceTotal > 0 {
+			recall = float64(matched) / float64(referenceTotal)
+		}
+		f1 := fScore(precision, recall, 1)
+
+		if bestIndex < 0 || f1 > bestF1 {
+			bestIndex = i
+			bestPrecision, bestRecall, bestF1 = precision, recall, f1
+		}
+	}
+
+	result.Score = bestF1
+	result.Metadata["variant"] = string(variant)
+	result.Metadata["precision"] = bestPrecision
+	result.Metadata["recall"] = bestRecall
+	result.Metadata["f1"] = bestF1
+	result.Metadata["reference_index"] = bestIndex
+
+	return result
+}
+
+// lcsLength returns the length of the longest common subsequence of a and b
+func lcsLength(a, b []string) int {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				curr[j] = prev[j-1] + 1
+			} else {
+				curr[j] = max(prev[j], curr[j-1])
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,116 @@
+package heuristic
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+func TestROUGE(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		opts          ROUGEOptions
+		output        string
+		expected      string
+		alternatives  []string
+		wantErr       bool
+		wantScore     float64
+		wantPrecision float64
+		wantRecall    float64
+	}{
+		{
+			name:          "rougeL identical",
+			opts:          ROUGEOptions{},
+			output:        "the cat sat on the mat",
+			expected:      "The cat sat on the mat.",
+			wantScore:     1.0,
+			wantPrecision: 1.0,
+			wantRecall:    1.0,
+		},
+		{
+			name:          "rougeL partial overlap",
+			opts:          ROUGEOptions{Variant: ROUGEL},
+			output:        "the cat sat on the mat",
+			expected:      "the cat is on the mat",
+			wantScore:     5.0 / 6.0,
+			wantPrecision: 5.0 / 6.0,
+			wantRecall:    5.0 / 6.0,
+		},
+		{
+			name:          "rouge1 subset output",
+			opts:          ROUGEOptions{Variant: ROUGE1},
+			output:        "the cat",
+			expected:      "the cat sat on the mat",
+			wantScore:     2 * 1.0 * (2.0 / 6.0) / (1.0 + 2.0/6.0),
+			wantPrecision: 1.0,
+			wantRecall:    2.0 / 6.0,
+		},
+		{
+			name:          "rouge2 partial overlap",
+			opts:          ROUGEOptions{Variant: ROUGE2},
+			output:        "the cat sat on the mat",
+			expected:      "the cat is on the mat",
+			wantScore:     3.0 / 5.0,
+			wantPrecision: 3.0 / 5.0,
+			wantRecall:    3.0 / 5.0,
+		},
+		{
+			name:          "best of multiple references",
+			opts:          ROUGEOptions{Variant: ROUGE1},
+			output:        "paris is the capital",
+			expected:      "london",
+			alternatives:  []string{"paris is the capital of france"},
+			wantScore:     2 * 1.0 * (4.0 / 6.0) / (1.0 + 4.0/6.0),
+			wantPrecision: 1.0,
+			wantRecall:    4.0 / 6.0,
+		},
+		{
+			name:     "unsupported variant",
+			opts:     ROUGEOptions{Variant: "rouge9"},
+			output:   "a",
+			expected: "a",
+			wantErr:  true,
+		},
+		{
+			name:     "no expected value",
+			opts:     ROUGEOptions{},
+			output:   "a",
+			expected: "",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scorer := ROUGE(tt.opts)
+			result := scorer.Score(ctx, api.ScoreInputs{Output: tt.output, Expected: tt.expected, ExpectedAlternatives: tt.alternatives})
+
+			if (result.Error != nil) != tt.wantErr {
+				t.Fatalf("ROUGE.Score() error = %v, wantErr %v", result.Error, tt.wantErr)
+			}
+
+			if math.Abs(result.Score-tt.wantScore) > 0.001 {
+				t.Errorf("ROUGE.Score() score = %v, wantScore %v", result.Score, tt.wantScore)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if p, _ := result.Metadata["precision"].(float64); math.Abs(p-tt.wantPrecision) > 0.001 {
+				t.Errorf("ROUGE.Score() precision = %v, want %v", p, tt.wantPrecision)
+			}
+			if r, _ := result.Metadata["recall"].(float64); math.Abs(r-tt.wantRecall) > 0.001 {
+				t.Errorf("ROUGE.Score() recall = %v, want %v", r, tt.wantRecall)
+			}
+
+			if result.Name != "ROUGE" {
+				t.Errorf("ROUGE.Score() name = %v, want 'ROUGE'", result.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package heuristic
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenizer splits text into tokens for n-gram based scorers
+type Tokenizer func(text string) []string
+
+// DefaultTokenizer lowercases the text and splits it into runs of letters and digits
+// Punctuation and whitespace act as separators and are dropped
+func DefaultTokenizer(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// WhitespaceTokenizer splits text on whitespace without any further normalization
+func WhitespaceTokenizer(text string) []string {
+	return strings.Fields(text)
+}
+
+// ngramCounts counts all n-grams of the given order in tokens
+func ngramCounts(tokens []string, n int) map[string]int {
+	counts := make(map[string]int)
+	for i := 0; i+n <= len(tokens); i++ {
+		counts[strings.Join(tokens[i:i+n], "\x00")]++
+	}
+	return counts
+}
+
+// overlapCount returns the number of n-grams shared by both counts, clipped by the smaller count
+func overlapCount(a, b map[string]int) int {
+	overlap := 0
+	for gram, countA := range a {
+		overlap += min(countA, b[gram])
+	}
+	return overlap
+}
+
+// fScore computes the weighted harmonic mean of precision and recall
+// beta > 1 weights recall higher, beta < 1 weights precision higher
+func fScore(precision, recall, beta float64) float64 {
+	if precision == 0 && recall == 0 {
+		return 0
+	}
+	beta2 := beta * beta
+	return (1 + beta2) * precision * recall / (beta2*precision + recall)
+}
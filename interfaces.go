@@ -5,9 +5,29 @@ import (
 )
 
 type LLMGenerator = api.LLMGenerator
+type MultimodalGenerator = api.MultimodalGenerator
+type Part = api.Part
+type UsageReportingGenerator = api.UsageReportingGenerator
+type GenerateResult = api.GenerateResult
+type Usage = api.Usage
 type Embedder = api.Embedder
+type BatchEmbedder = api.BatchEmbedder
+type BatchRequestCounter = api.BatchRequestCounter
 type ModerationProvider = api.ModerationProvider
 type ModerationCategory = api.ModerationCategory
 type ModerationResult = api.ModerationResult
 
 var ModerationCategories = api.ModerationCategories
+
+type ReferenceAggregation = api.ReferenceAggregation
+
+const (
+	AggregateBest  = api.AggregateBest
+	AggregateMean  = api.AggregateMean
+	AggregateWorst = api.AggregateWorst
+)
+
+var (
+	TextPart  = api.TextPart
+	ImagePart = api.ImagePart
+)
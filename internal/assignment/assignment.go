@@ -0,0 +1,100 @@
+// Package assignment solves the maximum-weight bipartite assignment problem
+package assignment
+
+import "math"
+
+// Optimal returns a one-to-one assignment of rows to columns maximizing the total weight
+// assignment[i] is the column matched to row i, or -1 if the row is left unmatched
+// (which only happens when there are more rows than columns)
+func Optimal(weights [][]float64) []int {
+	rows := len(weights)
+	if rows == 0 {
+		return nil
+	}
+	cols := len(weights[0])
+	assignment := make([]int, rows)
+	for i := range assignment {
+		assignment[i] = -1
+	}
+	if cols == 0 {
+		return assignment
+	}
+
+	// The Hungarian algorithm below requires rows <= cols, so solve the transposed problem otherwise
+	if rows > cols {
+		transposed := make([][]float64, cols)
+		for j := range transposed {
+			transposed[j] = make([]float64, rows)
+			for i := range rows {
+				transposed[j][i] = weights[i][j]
+			}
+		}
+		for j, i := range Optimal(transposed) {
+			if i >= 0 {
+				assignment[i] = j
+			}
+		}
+		return assignment
+	}
+
+	// Hungarian algorithm (Kuhn-Munkres) with potentials, minimizing negated weights
+	// Rows and columns are 1-indexed; index 0 is a sentinel
+	u := make([]float64, rows+1)
+	v := make([]float64, cols+1)
+	p := make([]int, cols+1) // p[j] is the row assigned to column j
+	way := make([]int, cols+1)
+
+	for i := 1; i <= rows; i++ {
+		p[0] = i
+		j0 := 0
+		minv := make([]float64, cols+1)
+		used := make([]bool, cols+1)
+		for j := range minv {
+			minv[j] = math.Inf(1)
+		}
+		for {
+			used[j0] = true
+			i0 := p[j0]
+			delta := math.Inf(1)
+			j1 := 0
+			for j := 1; j <= cols; j++ {
+				if used[j] {
+					continue
+				}
+				cur := -weights[i0-1][j-1] - u[i0] - v[j]
+				if cur < minv[j] {
+					minv[j] = cur
+					way[j] = j0
+				}
+				if minv[j] < delta {
+					delta = minv[j]
+					j1 = j
+				}
+			}
+			for j := 0; j <= cols; j++ {
+				if used[j] {
+					u[p[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+			if p[j0] == 0 {
+				break
+			}
+		}
+		for j0 != 0 {
+			j1 := way[j0]
+			p[j0] = p[j1]
+			j0 = j1
+		}
+	}
+
+	for j := 1; j <= cols; j++ {
+		if p[j] > 0 {
+			assignment[p[j]-1] = j - 1
+		}
+	}
+	return assignment
+}
@@ -0,0 +1,43 @@
+package assignment
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOptimal(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights [][]float64
+		want    []int
+	}{
+		{
+			name:    "greedy would be suboptimal",
+			weights: [][]float64{{0.9, 0.8}, {0.85, 0.1}},
+			want:    []int{1, 0},
+		},
+		{
+			name:    "more columns than rows",
+			weights: [][]float64{{0.1, 0.2, 0.9}},
+			want:    []int{2},
+		},
+		{
+			name:    "more rows than columns",
+			weights: [][]float64{{0.2}, {0.9}, {0.5}},
+			want:    []int{-1, 0, -1},
+		},
+		{
+			name:    "empty",
+			weights: nil,
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Optimal(tt.weights); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Optimal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package testutils
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -62,6 +65,38 @@ func NewHypertClient(t *testing.T, config HypertClientConfig) *http.Client {
 	return hypertClient
 }
 
+// compactJSONTransport wraps an http.RoundTripper to strip insignificant whitespace from JSON request bodies
+// protojson (used by Google Cloud REST clients) randomly inserts spaces depending on the binary being run,
+// which would otherwise change the content hash hypert uses to name cached requests
+type compactJSONTransport struct {
+	base http.RoundTripper
+}
+
+func (t *compactJSONTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return t.base.RoundTrip(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	_ = req.Body.Close()
+
+	var compacted bytes.Buffer
+	if json.Valid(body) && json.Compact(&compacted, body) == nil {
+		body = compacted.Bytes()
+	}
+
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return t.base.RoundTrip(req)
+}
+
 // quotaProjectTransport wraps an http.RoundTripper to add quota project header
 type quotaProjectTransport struct {
 	base      http.RoundTripper
@@ -95,6 +130,7 @@ func NewAuthenticatedHypertClient(t *testing.T, config HypertClientConfig, proje
 			hypert.MethodValidator(),
 		)),
 	)
+	hypertClient.Transport = &compactJSONTransport{base: hypertClient.Transport}
 
 	// If we're in record mode, wrap with OAuth2 authentication and set quota project
 	if ShouldUpdate() {
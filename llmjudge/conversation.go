@@ -0,0 +1,256 @@
+package llmjudge
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// GoalCompletionOptions configures the GoalCompletion scorer
+type GoalCompletionOptions struct {
+	// Goal describes what the user wants to achieve (default: Input, or inferred from the conversation)
+	Goal string
+}
+
+// GoalCompletion returns a scorer that uses an LLM to judge whether the user's goal was accomplished over a conversation
+// The conversation is Messages followed by Output as the final assistant turn; Expected optionally describes the desired outcome
+func GoalCompletion(llm api.LLMGenerator, opts GoalCompletionOptions) api.Scorer {
+	return &conversationScorer{
+		name: "GoalCompletion",
+		llm:  llm,
+		prompt: func(in api.ScoreInputs, transcript string) string {
+			goal := opts.Goal
+			if goal == "" {
+				goal = in.Input
+			}
+			return fmt.Sprintf(goalCompletionPromptTemplate, orNotProvided(goal), orNotProvided(in.Expected), transcript)
+		},
+		choiceDescription: "Goal completion: (A) fully accomplished (EXCELLENT), (B) mostly accomplished (GOOD), (C) partially accomplished (FAIR), (D) little progress (POOR), (E) not accomplished (FAIL)",
+	}
+}
+
+// ConversationCoherenceOptions configures the ConversationCoherence scorer
+type ConversationCoherenceOptions struct {
+	// Additional configuration options can be added here
+}
+
+// ConversationCoherence returns a scorer that uses an LLM to judge whether the assistant stays consistent
+// and builds on earlier turns across a conversation
+func ConversationCoherence(llm api.LLMGenerator, opts ConversationCoherenceOptions) api.Scorer {
+	return &conversationScorer{
+		name: "ConversationCoherence",
+		llm:  llm,
+		prompt: func(in api.ScoreInputs, transcript string) string {
+			return fmt.Sprintf(coherencePromptTemplate, transcript)
+		},
+		choiceDescription: "Coherence: (A) fully coherent (EXCELLENT), (B) minor lapses (GOOD), (C) noticeable lapses (FAIR), (D) frequent lapses (POOR), (E) incoherent or contradictory (FAIL)",
+	}
+}
+
+// UserFrustrationOptions configures the UserFrustration scorer
+type UserFrustrationOptions struct {
+	// Additional configuration options can be added here
+}
+
+// UserFrustration returns a scorer that uses an LLM to detect signs of user frustration in a conversation
+// Returns 1.0 when the user shows no frustration and 0.0 for severe frustration
+func UserFrustration(llm api.LLMGenerator, opts UserFrustrationOptions) api.Scorer {
+	return &conversationScorer{
+		name: "UserFrustration",
+		llm:  llm,
+		prompt: func(in api.ScoreInputs, transcript string) string {
+			return fmt.Sprintf(userFrustrationPromptTemplate, transcript)
+		},
+		choiceDescription: "User frustration: (A) none, (B) slight, (C) moderate, (D) strong, (E) severe",
+	}
+}
+
+const goalCompletionPromptTemplate = `You are evaluating whether an assistant helped a user accomplish their goal in a conversation. Here is the data:
+[BEGIN DATA]
+************
+[Goal]: %s
+************
+[Expected Outcome]: %s
+************
+[Conversation]:
+%s
+************
+[END DATA]
+
+Judge whether the user's goal was accomplished by the end of the conversation. If no goal is stated, infer it from the user's messages. If an expected outcome is provided, use it as the definition of success.
+Select one of the following options:
+(A) The goal was fully accomplished (EXCELLENT).
+(B) The goal was mostly accomplished; only minor details are missing (GOOD).
+(C) The goal was partially accomplished (FAIR).
+(D) Little progress was made toward the goal (POOR).
+(E) The goal was not accomplished, or the assistant worked against it (FAIL).
+
+Provide your assessment with a choice (A, B, C, D, or E), a detailed explanation of your reasoning, and quotes from the conversation as evidence.`
+
+const coherencePromptTemplate = `You are evaluating the coherence of an assistant across a multi-turn conversation. Here is the data:
+[BEGIN DATA]
+************
+[Conversation]:
+%s
+************
+[END DATA]
+
+A coherent assistant stays consistent with what it said earlier, remembers information the user already provided, answers what was actually asked in each turn, and does not contradict itself or tool results.
+Select one of the following options:
+(A) Every assistant turn is consistent and builds on the earlier conversation (EXCELLENT).
+(B) Mostly coherent with minor lapses, such as a small repetition (GOOD).
+(C) Noticeable lapses, such as asking again for information already given (FAIR).
+(D) Frequent lapses or a turn that ignores the context of the conversation (POOR).
+(E) The assistant contradicts itself or the conversation does not hang together (FAIL).
+
+Provide your assessment with a choice (A, B, C, D, or E), a detailed explanation of your reasoning, and quotes from the conversation as evidence.`
+
+const userFrustrationPromptTemplate = `You are evaluating how frustrated the user is in a conversation with an assistant. Here is the data:
+[BEGIN DATA]
+************
+[Conversation]:
+%s
+************
+[END DATA]
+
+Look for signs of frustration in the user's messages: repeating or rephrasing the same request, corrections of the assistant, complaints, sarcasm, all caps, asking for a human, or giving up.
+Select one of the following options:
+(A) The user shows no frustration.
+(B) The user shows slight impatience.
+(C) The user is clearly frustrated in at least one turn.
+(D) The user is strongly frustrated, repeatedly correcting or complaining.
+(E) The user is severely frustrated: angry, demanding escalation, or abandoning the conversation.
+
+Provide your assessment with a choice (A, B, C, D, or E), a detailed explanation of your reasoning, and quotes from the user's messages as evidence.`
+
+// conversationScorer is a single-choice A–E judge over a rendered conversation transcript
+type conversationScorer struct {
+	name              string
+	llm               api.LLMGenerator
+	prompt            func(in api.ScoreInputs, transcript string) string
+	choiceDescription string
+}
+
+func (s *conversationScorer) Score(ctx context.Context, in api.ScoreInputs) api.Score {
+	result := api.Score{
+		Name:     s.name,
+		Metadata: make(map[string]any),
+	}
+
+	transcript := in.Transcript()
+	if len(transcript) == 0 {
+		result.Error = api.ErrNoConversation
+		result.Score = 0
+		return result
+	}
+
+	if s.llm == nil {
+		result.Error = fmt.Errorf("LLM generator is required")
+		result.Score = 0
+		return result
+	}
+	recordGenerationConfig(result.Metadata, s.llm)
+
+	judgement, err := judgeChoice(ctx, s.llm, s.prompt(in, renderTranscript(transcript)), s.choiceDescription, in.Attachments)
+	recordUsage(result.Metadata, judgement.usage)
+	if judgement.rawResponse != nil {
+		result.Metadata["raw_response"] = judgement.rawResponse
+	}
+	if err != nil {
+		result.Error = err
+		result.Score = 0
+		return result
+	}
+
+	result.Score = judgement.score
+	result.Metadata["choice"] = judgement.choice
+	result.Metadata["explanation"] = judgement.explanation
+	result.Metadata["evidence"] = judgement.evidence
+	result.Metadata["turns"] = len(transcript)
+
+	return result
+}
+
+// choiceJudgement is a single A–E verdict of an LLM judge
+type choiceJudgement struct {
+	choice      string
+	explanation string
+	evidence    []string
+	score       float64
+	rawResponse map[string]interface{}
+	usage       api.Usage
+}
+
+// judgeChoice asks the LLM for a single A–E choice with explanation and evidence, and maps it to [0,1]
+func judgeChoice(ctx context.Context, llm api.LLMGenerator, prompt, choiceDescription string, attachments []api.Part) (choiceJudgement, error) {
+	// Define schema for structured response
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"choice": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"A", "B", "C", "D", "E"},
+				"description": choiceDescription,
+			},
+			"explanation": map[string]interface{}{
+				"type":        "string",
+				"description": "Detailed explanation of the assessment",
+			},
+			"evidence": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string"},
+			},
+		},
+		"required": []string{"choice", "explanation"},
+	}
+
+	structuredResponse, usage, err := structuredGenerate(ctx, llm, prompt, schema, attachments)
+	if err != nil {
+		return choiceJudgement{usage: usage}, fmt.Errorf("LLM generation failed: %w", err)
+	}
+
+	choice, ok := structuredResponse["choice"].(string)
+	if !ok {
+		return choiceJudgement{rawResponse: structuredResponse, usage: usage}, fmt.Errorf("failed to extract choice from structured response")
+	}
+
+	// Map A–E to [0,1] using school-style grading (A=best, E=worst)
+	choiceScores := map[string]float64{
+		"A": 1.0,
+		"B": 0.75,
+		"C": 0.5,
+		"D": 0.25,
+		"E": 0.0,
+	}
+	score, ok := choiceScores[choice]
+	if !ok {
+		return choiceJudgement{rawResponse: structuredResponse, usage: usage}, fmt.Errorf("unexpected choice %q in structured response", choice)
+	}
+
+	explanation, _ := structuredResponse["explanation"].(string)
+	evidence := []string{}
+	if items, ok := structuredResponse["evidence"].([]interface{}); ok {
+		for _, item := range items {
+			if quote, ok := item.(string); ok {
+				evidence = append(evidence, quote)
+			}
+		}
+	}
+
+	return choiceJudgement{
+		choice:      choice,
+		explanation: explanation,
+		evidence:    evidence,
+		score:       score,
+		rawResponse: structuredResponse,
+		usage:       usage,
+	}, nil
+}
+
+func orNotProvided(text string) string {
+	if text == "" {
+		return "(not provided)"
+	}
+	return text
+}
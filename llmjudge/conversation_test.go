@@ -0,0 +1,258 @@
+package llmjudge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// mockLLMGeneratorCapture records the last prompt and returns a fixed structured response
+type mockLLMGeneratorCapture struct {
+	response map[string]interface{}
+	err      error
+	prompt   string
+}
+
+func (m *mockLLMGeneratorCapture) Generate(ctx context.Context, prompt string) (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
+func (m *mockLLMGeneratorCapture) StructuredGenerate(ctx context.Context, prompt string, schema map[string]interface{}) (map[string]interface{}, error) {
+	m.prompt = prompt
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.response, nil
+}
+
+var testConversation = []api.Message{
+	{Role: api.RoleUser, Content: "I need to change my flight to Friday."},
+	{Role: api.RoleAssistant, Content: "Let me look that up.", ToolCalls: []api.ToolCall{
+		{ID: "call_1", Name: "find_booking", Arguments: map[string]any{"user": "u42"}},
+	}},
+	{Role: api.RoleTool, Content: `{"booking": "XY123"}`},
+}
+
+func TestConversationJudges_Unit(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		scorer      func(llm api.LLMGenerator) api.Scorer
+		wantName    string
+		response    map[string]interface{}
+		llmErr      error
+		in          api.ScoreInputs
+		wantErr     error
+		wantAnyErr  bool
+		wantScore   float64
+		wantInPrmpt []string
+	}{
+		{
+			name:     "goal completion uses input as goal",
+			scorer:   func(llm api.LLMGenerator) api.Scorer { return GoalCompletion(llm, GoalCompletionOptions{}) },
+			wantName: "GoalCompletion",
+			response: map[string]interface{}{"choice": "B", "explanation": "mostly done", "evidence": []interface{}{"moved to Friday"}},
+			in: api.ScoreInputs{
+				Input:    "Rebook flight XY123",
+				Messages: testConversation,
+				Output:   "Your flight XY123 is moved to Friday.",
+			},
+			wantScore: 0.75,
+			wantInPrmpt: []string{
+				"[Goal]: Rebook flight XY123",
+				"[Expected Outcome]: (not provided)",
+				"[user]: I need to change my flight to Friday.",
+				`  -> tool call find_booking({"user":"u42"}) id=call_1`,
+				`[tool]: {"booking": "XY123"}`,
+				"[assistant]: Your flight XY123 is moved to Friday.",
+			},
+		},
+		{
+			name: "goal completion option overrides input",
+			scorer: func(llm api.LLMGenerator) api.Scorer {
+				return GoalCompletion(llm, GoalCompletionOptions{Goal: "cancel booking"})
+			},
+			response: map[string]interface{}{"choice": "E", "explanation": "not cancelled"},
+			wantName: "GoalCompletion",
+			in: api.ScoreInputs{
+				Input:    "ignored",
+				Expected: "booking cancelled with refund",
+				Messages: testConversation,
+			},
+			wantScore:   0.0,
+			wantInPrmpt: []string{"[Goal]: cancel booking", "[Expected Outcome]: booking cancelled with refund"},
+		},
+		{
+			name: "coherence",
+			scorer: func(llm api.LLMGenerator) api.Scorer {
+				return ConversationCoherence(llm, ConversationCoherenceOptions{})
+			},
+			wantName:    "ConversationCoherence",
+			response:    map[string]interface{}{"choice": "A", "explanation": "consistent"},
+			in:          api.ScoreInputs{Messages: testConversation},
+			wantScore:   1.0,
+			wantInPrmpt: []string{"[assistant]: Let me look that up."},
+		},
+		{
+			name:      "frustration detected",
+			scorer:    func(llm api.LLMGenerator) api.Scorer { return UserFrustration(llm, UserFrustrationOptions{}) },
+			wantName:  "UserFrustration",
+			response:  map[string]interface{}{"choice": "D", "explanation": "repeated complaints"},
+			in:        api.ScoreInputs{Messages: testConversation, Output: "Sorry, I can't help."},
+			wantScore: 0.25,
+		},
+		{
+			name:      "output alone is a conversation",
+			scorer:    func(llm api.LLMGenerator) api.Scorer { return UserFrustration(llm, UserFrustrationOptions{}) },
+			wantName:  "UserFrustration",
+			response:  map[string]interface{}{"choice": "A", "explanation": "none"},
+			in:        api.ScoreInputs{Output: "Hello!"},
+			wantScore: 1.0,
+		},
+		{
+			name: "no conversation",
+			scorer: func(llm api.LLMGenerator) api.Scorer {
+				return ConversationCoherence(llm, ConversationCoherenceOptions{})
+			},
+			wantName: "ConversationCoherence",
+			in:       api.ScoreInputs{Input: "question only"},
+			wantErr:  api.ErrNoConversation,
+		},
+		{
+			name:       "llm error",
+			scorer:     func(llm api.LLMGenerator) api.Scorer { return GoalCompletion(llm, GoalCompletionOptions{}) },
+			wantName:   "GoalCompletion",
+			llmErr:     fmt.Errorf("API error"),
+			in:         api.ScoreInputs{Messages: testConversation},
+			wantAnyErr: true,
+		},
+		{
+			name:       "invalid choice",
+			scorer:     func(llm api.LLMGenerator) api.Scorer { return GoalCompletion(llm, GoalCompletionOptions{}) },
+			wantName:   "GoalCompletion",
+			response:   map[string]interface{}{"choice": "Z", "explanation": "?"},
+			in:         api.ScoreInputs{Messages: testConversation},
+			wantAnyErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := &mockLLMGeneratorCapture{response: tt.response, err: tt.llmErr}
+			result := tt.scorer(llm).Score(ctx, tt.in)
+
+			if result.Name != tt.wantName {
+				t.Errorf("Score() name = %v, want %v", result.Name, tt.wantName)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(result.Error, tt.wantErr) {
+					t.Errorf("Score() error = %v, want %v", result.Error, tt.wantErr)
+				}
+				return
+			}
+			if tt.wantAnyErr {
+				if result.Error == nil {
+					t.Error("Score() expected error but got none")
+				}
+				if result.Score != 0 {
+					t.Errorf("Score() score = %v, want 0 on error", result.Score)
+				}
+				return
+			}
+			if result.Error != nil {
+				t.Fatalf("Score() unexpected error = %v", result.Error)
+			}
+			if result.Score != tt.wantScore {
+				t.Errorf("Score() score = %v, want %v", result.Score, tt.wantScore)
+			}
+			if result.Metadata["choice"] != tt.response["choice"] {
+				t.Errorf("Score() choice = %v, want %v", result.Metadata["choice"], tt.response["choice"])
+			}
+			for _, want := range tt.wantInPrmpt {
+				if !strings.Contains(llm.prompt, want) {
+					t.Errorf("prompt missing %q\nprompt:\n%s", want, llm.prompt)
+				}
+			}
+		})
+	}
+}
+
+func TestConversationJudges_NoLLM(t *testing.T) {
+	ctx := context.Background()
+
+	scorers := []api.Scorer{
+		GoalCompletion(nil, GoalCompletionOptions{}),
+		ConversationCoherence(nil, ConversationCoherenceOptions{}),
+		UserFrustration(nil, UserFrustrationOptions{}),
+	}
+	for _, scorer := range scorers {
+		result := scorer.Score(ctx, api.ScoreInputs{Messages: testConversation})
+		if result.Error == nil {
+			t.Errorf("%s.Score() expected error when LLM is nil", result.Name)
+		}
+	}
+}
+
+func TestPromptContext(t *testing.T) {
+	// Without messages the prompt slot must be exactly Input, keeping existing prompts unchanged
+	if got := promptContext(api.ScoreInputs{Input: "question"}); got != "question" {
+		t.Errorf("promptContext() = %q, want %q", got, "question")
+	}
+
+	got := promptContext(api.ScoreInputs{
+		Input:    "Customer complaint",
+		Messages: []api.Message{{Role: api.RoleUser, Content: "Where is my order?"}},
+	})
+	want := "Customer complaint\n\nConversation so far:\n[user]: Where is my order?"
+	if got != want {
+		t.Errorf("promptContext() = %q, want %q", got, want)
+	}
+
+	llm := &mockLLMGeneratorCapture{response: map[string]interface{}{"choice": "A", "explanation": "ok"}}
+	Factuality(llm, FactualityOptions{}).Score(context.Background(), api.ScoreInputs{
+		Messages: []api.Message{{Role: api.RoleUser, Content: "What is 2+2?"}},
+		Output:   "4",
+		Expected: "4",
+	})
+	if !strings.Contains(llm.prompt, "[Question]: Conversation so far:\n[user]: What is 2+2?") {
+		t.Errorf("Factuality prompt does not render the conversation:\n%s", llm.prompt)
+	}
+}
+
+// mockConfigReporter is a generator that reports its generation settings
+type mockConfigReporter struct {
+	mockLLMGeneratorCapture
+}
+
+func (m *mockConfigReporter) GenerationConfig() map[string]any {
+	return map[string]any{"model": "test-model", "temperature": 0.0}
+}
+
+func TestGenerationConfigMetadata(t *testing.T) {
+	ctx := context.Background()
+	llm := &mockConfigReporter{mockLLMGeneratorCapture{response: map[string]interface{}{"choice": "A", "explanation": "ok"}}}
+
+	scorers := []api.Scorer{
+		Factuality(llm, FactualityOptions{}),
+		GoalCompletion(llm, GoalCompletionOptions{}),
+		TrajectoryReasonableness(llm, TrajectoryReasonablenessOptions{}),
+	}
+	in := api.ScoreInputs{
+		Output:     "4",
+		Expected:   "4",
+		Messages:   testConversation,
+		Trajectory: []api.Step{{Type: api.StepThought, Content: "add"}},
+	}
+	for _, scorer := range scorers {
+		result := scorer.Score(ctx, in)
+		config, ok := result.Metadata["generation_config"].(map[string]any)
+		if !ok || config["model"] != "test-model" {
+			t.Errorf("%s generation_config = %v, want reported config", result.Name, result.Metadata["generation_config"])
+		}
+	}
+}
@@ -9,11 +9,13 @@ import (
 
 // FactualityOptions configures the Factuality scorer
 type FactualityOptions struct {
-	// Additional configuration options can be added here
+	// Aggregation combines results across Expected and ExpectedAlternatives (default: api.AggregateBest)
+	Aggregation api.ReferenceAggregation
 }
 
 // Factuality returns a scorer that uses an LLM to evaluate if the output is factually consistent with the expected answer
 // This scorer uses chain-of-thought reasoning to determine factuality
+// When ExpectedAlternatives are given, each reference is judged in a separate LLM call
 func Factuality(llm api.LLMGenerator, opts FactualityOptions) api.Scorer {
 	return &factualityScorer{
 		opts: opts,
@@ -53,7 +55,8 @@ func (s *factualityScorer) Score(ctx context.Context, in api.ScoreInputs) api.Sc
 		Metadata: make(map[string]any),
 	}
 
-	if in.Expected == "" {
+	references := in.References()
+	if len(references) == 0 {
 		result.Error = api.ErrNoExpectedValue
 		result.Score = 0
 		return result
@@ -64,8 +67,58 @@ func (s *factualityScorer) Score(ctx context.Context, in api.ScoreInputs) api.Sc
 		result.Score = 0
 		return result
 	}
+	recordGenerationConfig(result.Metadata, s.llm)
+
+	judgements := make([]factualityJudgement, len(references))
+	scores := make([]float64, len(references))
+	var usage api.Usage
+	for i, ref := range references {
+		judgement, err := s.judge(ctx, promptContext(in), ref, in.Output, in.Attachments)
+		usage.Add(judgement.usage)
+		recordUsage(result.Metadata, usage)
+		if err != nil {
+			result.Error = err
+			result.Score = 0
+			if judgement.rawResponse != nil {
+				result.Metadata["raw_response"] = judgement.rawResponse
+			}
+			return result
+		}
+		judgements[i] = judgement
+		scores[i] = judgement.score
+	}
+
+	score, index := s.opts.Aggregation.Aggregate(scores)
+
+	result.Score = score
+	result.Metadata["choice"] = judgements[index].choice
+	result.Metadata["explanation"] = judgements[index].explanation
+	result.Metadata["raw_response"] = judgements[index].rawResponse
+	result.Metadata["reference_index"] = index
+	if len(references) > 1 {
+		choices := make([]string, len(judgements))
+		for i, j := range judgements {
+			choices[i] = j.choice
+		}
+		result.Metadata["reference_scores"] = scores
+		result.Metadata["reference_choices"] = choices
+	}
+
+	return result
+}
+
+// factualityJudgement is the LLM verdict for a single reference
+type factualityJudgement struct {
+	choice      string
+	explanation string
+	score       float64
+	rawResponse map[string]interface{}
+	usage       api.Usage
+}
 
-	prompt := fmt.Sprintf(factualityPromptTemplate, in.Input, in.Expected, in.Output)
+// judge asks the LLM to compare the submission against a single expert answer
+func (s *factualityScorer) judge(ctx context.Context, input, expected, output string, attachments []api.Part) (factualityJudgement, error) {
+	prompt := fmt.Sprintf(factualityPromptTemplate, input, expected, output)
 
 	// Define schema for structured response
 	schema := map[string]interface{}{
@@ -85,28 +138,20 @@ func (s *factualityScorer) Score(ctx context.Context, in api.ScoreInputs) api.Sc
 	}
 
 	// Use StructuredGenerate to get structured response
-	structuredResponse, err := s.llm.StructuredGenerate(ctx, prompt, schema)
+	structuredResponse, usage, err := structuredGenerate(ctx, s.llm, prompt, schema, attachments)
 	if err != nil {
-		result.Error = fmt.Errorf("LLM generation failed: %v", err)
-		result.Score = 0
-		return result
+		return factualityJudgement{usage: usage}, fmt.Errorf("LLM generation failed: %w", err)
 	}
 
 	// Extract choice and explanation from structured response
 	choice, ok := structuredResponse["choice"].(string)
 	if !ok {
-		result.Error = fmt.Errorf("failed to extract choice from structured response")
-		result.Score = 0
-		result.Metadata["raw_response"] = structuredResponse
-		return result
+		return factualityJudgement{rawResponse: structuredResponse, usage: usage}, fmt.Errorf("failed to extract choice from structured response")
 	}
 
 	explanation, ok := structuredResponse["explanation"].(string)
 	if !ok {
-		result.Error = fmt.Errorf("failed to extract explanation from structured response")
-		result.Score = 0
-		result.Metadata["raw_response"] = structuredResponse
-		return result
+		return factualityJudgement{rawResponse: structuredResponse, usage: usage}, fmt.Errorf("failed to extract explanation from structured response")
 	}
 
 	// Map choice to score using school-style grading (A=best, E=worst)
@@ -118,10 +163,11 @@ func (s *factualityScorer) Score(ctx context.Context, in api.ScoreInputs) api.Sc
 		"E": 0.0, // disagreement
 	}
 
-	result.Score = choiceScores[choice]
-	result.Metadata["choice"] = choice
-	result.Metadata["explanation"] = explanation
-	result.Metadata["raw_response"] = structuredResponse
-
-	return result
+	return factualityJudgement{
+		choice:      choice,
+		explanation: explanation,
+		score:       choiceScores[choice],
+		rawResponse: structuredResponse,
+		usage:       usage,
+	}, nil
 }
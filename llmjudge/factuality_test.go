@@ -3,7 +3,10 @@ package llmjudge
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"strings"
 	"testing"
 
 	"github.com/datar-psa/goeval/api"
@@ -183,3 +186,171 @@ func TestFactuality_NoLLM(t *testing.T) {
 		t.Errorf("Factuality.Score() score = %v, want 0", result.Score)
 	}
 }
+
+// mockLLMGeneratorByExpert returns a response chosen by the expert answer embedded in the prompt
+type mockLLMGeneratorByExpert struct {
+	responses map[string]map[string]interface{}
+	prompts   []string
+}
+
+func (m *mockLLMGeneratorByExpert) StructuredGenerate(ctx context.Context, prompt string, schema map[string]interface{}) (map[string]interface{}, error) {
+	m.prompts = append(m.prompts, prompt)
+	for expert, response := range m.responses {
+		if strings.Contains(prompt, "[Expert]: "+expert+"\n") {
+			return response, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected prompt")
+}
+
+func TestFactuality_MultipleReferences(t *testing.T) {
+	ctx := context.Background()
+
+	mockLLM := &mockLLMGeneratorByExpert{responses: map[string]map[string]interface{}{
+		"Paris":               {"choice": "A", "explanation": "Same answer."},
+		"The capital is Lyon": {"choice": "E", "explanation": "Disagreement."},
+		"Paris, on the Seine": {"choice": "D", "explanation": "Subset."},
+	}}
+	in := api.ScoreInputs{
+		Input:                "What is the capital of France?",
+		Output:               "Paris",
+		Expected:             "The capital is Lyon",
+		ExpectedAlternatives: []string{"Paris", "Paris, on the Seine"},
+	}
+
+	tests := []struct {
+		name        string
+		aggregation api.ReferenceAggregation
+		wantScore   float64
+		wantIndex   int
+		wantChoice  string
+	}{
+		{name: "best", aggregation: api.AggregateBest, wantScore: 1.0, wantIndex: 1, wantChoice: "A"},
+		{name: "mean", aggregation: api.AggregateMean, wantScore: (0.0 + 1.0 + 0.4) / 3, wantIndex: 1, wantChoice: "A"},
+		{name: "worst", aggregation: api.AggregateWorst, wantScore: 0.0, wantIndex: 0, wantChoice: "E"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLLM.prompts = nil
+			result := Factuality(mockLLM, FactualityOptions{Aggregation: tt.aggregation}).Score(ctx, in)
+
+			if result.Error != nil {
+				t.Fatalf("Factuality.Score() unexpected error = %v", result.Error)
+			}
+			if len(mockLLM.prompts) != 3 {
+				t.Errorf("Factuality.Score() made %d LLM calls, want 3", len(mockLLM.prompts))
+			}
+			if math.Abs(result.Score-tt.wantScore) > 1e-9 {
+				t.Errorf("Factuality.Score() score = %v, wantScore %v", result.Score, tt.wantScore)
+			}
+			if result.Metadata["reference_index"] != tt.wantIndex {
+				t.Errorf("Factuality.Score() reference_index = %v, want %v", result.Metadata["reference_index"], tt.wantIndex)
+			}
+			if result.Metadata["choice"] != tt.wantChoice {
+				t.Errorf("Factuality.Score() choice = %v, want %v", result.Metadata["choice"], tt.wantChoice)
+			}
+		})
+	}
+}
+
+// mockMultimodalGenerator records content parts passed to StructuredGenerateContent
+type mockMultimodalGenerator struct {
+	mockLLMGeneratorCapture
+	parts []api.Part
+}
+
+func (m *mockMultimodalGenerator) StructuredGenerateContent(ctx context.Context, parts []api.Part, schema map[string]interface{}) (map[string]interface{}, error) {
+	m.parts = parts
+	return m.response, nil
+}
+
+func TestFactuality_Attachments(t *testing.T) {
+	ctx := context.Background()
+	image := api.ImagePart([]byte{0x89, 'P', 'N', 'G'}, "image/png")
+	in := api.ScoreInputs{
+		Input:       "What animal is in the picture?",
+		Attachments: []api.Part{image},
+		Output:      "A cat",
+		Expected:    "A cat sitting on a sofa",
+	}
+
+	llm := &mockMultimodalGenerator{
+		mockLLMGeneratorCapture: mockLLMGeneratorCapture{response: map[string]interface{}{"choice": "D", "explanation": "subset"}},
+	}
+	result := Factuality(llm, FactualityOptions{}).Score(ctx, in)
+	if result.Error != nil {
+		t.Fatalf("Factuality.Score() unexpected error = %v", result.Error)
+	}
+	if result.Score != 0.4 {
+		t.Errorf("Factuality.Score() score = %v, want 0.4", result.Score)
+	}
+	if llm.prompt != "" {
+		t.Error("Factuality.Score() used the text-only method despite attachments")
+	}
+	if len(llm.parts) != 3 {
+		t.Fatalf("parts = %d, want prompt, note and image", len(llm.parts))
+	}
+	if !strings.Contains(llm.parts[0].Text, "[Question]: What animal is in the picture?") {
+		t.Errorf("first part is not the judge prompt: %q", llm.parts[0].Text)
+	}
+	if llm.parts[2].MIMEType != "image/png" || len(llm.parts[2].Data) != 4 {
+		t.Errorf("last part = %+v, want the inline image", llm.parts[2])
+	}
+
+	// A text-only generator cannot see the image
+	textOnly := &mockLLMGeneratorCapture{response: map[string]interface{}{"choice": "A", "explanation": "same"}}
+	result = Factuality(textOnly, FactualityOptions{}).Score(ctx, in)
+	if !errors.Is(result.Error, api.ErrMultimodalNotSupported) {
+		t.Errorf("Factuality.Score() error = %v, want %v", result.Error, api.ErrMultimodalNotSupported)
+	}
+	if result.Score != 0 {
+		t.Errorf("Factuality.Score() score = %v, want 0", result.Score)
+	}
+}
+
+// mockUsageGenerator reports fixed token usage for every call
+type mockUsageGenerator struct {
+	mockLLMGeneratorCapture
+	usage api.Usage
+}
+
+func (m *mockUsageGenerator) StructuredGenerateContentWithUsage(ctx context.Context, parts []api.Part, schema map[string]interface{}) (api.GenerateResult, error) {
+	m.prompt = parts[0].Text
+	return api.GenerateResult{Data: m.response, Usage: m.usage}, m.err
+}
+
+func TestFactuality_Usage(t *testing.T) {
+	llm := &mockUsageGenerator{
+		mockLLMGeneratorCapture: mockLLMGeneratorCapture{response: map[string]interface{}{"choice": "A", "explanation": "same"}},
+		usage:                   api.Usage{Model: "judge", Calls: 1, PromptTokens: 100, OutputTokens: 20, ThinkingTokens: 5},
+	}
+
+	result := Factuality(llm, FactualityOptions{}).Score(context.Background(), api.ScoreInputs{
+		Output:               "Paris",
+		Expected:             "Paris",
+		ExpectedAlternatives: []string{"Paris, France"},
+	})
+	if result.Error != nil {
+		t.Fatalf("Factuality.Score() unexpected error = %v", result.Error)
+	}
+
+	// One call per reference
+	want := api.Usage{Model: "judge", Calls: 2, PromptTokens: 200, OutputTokens: 40, ThinkingTokens: 10}
+	if got := result.Metadata["usage"]; got != want {
+		t.Errorf("Factuality.Score() usage = %+v, want %+v", got, want)
+	}
+	if !strings.Contains(llm.prompt, "[Expert]: Paris, France") {
+		t.Errorf("prompt not sent through the usage-reporting method:\n%s", llm.prompt)
+	}
+
+	// Usage is recorded even when the call fails
+	llm.err = fmt.Errorf("quota exceeded")
+	result = Factuality(llm, FactualityOptions{}).Score(context.Background(), api.ScoreInputs{Output: "Paris", Expected: "Paris"})
+	if result.Error == nil {
+		t.Fatal("Factuality.Score() expected error")
+	}
+	if got, ok := result.Metadata["usage"].(api.Usage); !ok || got.Calls != 1 {
+		t.Errorf("Factuality.Score() usage on error = %v", result.Metadata["usage"])
+	}
+}
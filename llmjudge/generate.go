@@ -0,0 +1,54 @@
+package llmjudge
+
+import (
+	"context"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// attachmentsNote introduces the attachments that follow the judge prompt
+const attachmentsNote = "[Attachments]: the following media were given to the model together with the question and are part of the data."
+
+// structuredGenerate sends prompt to the LLM, followed by attachments when there are any
+// Attachments require a generator implementing api.MultimodalGenerator or api.UsageReportingGenerator
+// Token usage is returned when the generator implements api.UsageReportingGenerator
+func structuredGenerate(ctx context.Context, llm api.LLMGenerator, prompt string, schema map[string]interface{}, attachments []api.Part) (map[string]interface{}, api.Usage, error) {
+	parts := []api.Part{api.TextPart(prompt)}
+	if len(attachments) > 0 {
+		parts = append(parts, api.TextPart(attachmentsNote))
+		parts = append(parts, attachments...)
+	}
+
+	if reporting, ok := llm.(api.UsageReportingGenerator); ok {
+		result, err := reporting.StructuredGenerateContentWithUsage(ctx, parts, schema)
+		return result.Data, result.Usage, err
+	}
+
+	if len(attachments) == 0 {
+		data, err := llm.StructuredGenerate(ctx, prompt, schema)
+		return data, api.Usage{}, err
+	}
+
+	multimodal, ok := llm.(api.MultimodalGenerator)
+	if !ok {
+		return nil, api.Usage{}, api.ErrMultimodalNotSupported
+	}
+	data, err := multimodal.StructuredGenerateContent(ctx, parts, schema)
+	return data, api.Usage{}, err
+}
+
+// recordUsage stores token usage in metadata when the generator reported any
+func recordUsage(metadata map[string]any, usage api.Usage) {
+	if usage.Calls > 0 {
+		metadata["usage"] = usage
+	}
+}
+
+// recordGenerationConfig stores the generator's effective settings in metadata, if it reports them
+func recordGenerationConfig(metadata map[string]any, llm api.LLMGenerator) {
+	if reporter, ok := llm.(api.GenerationConfigReporter); ok {
+		if config := reporter.GenerationConfig(); config != nil {
+			metadata["generation_config"] = config
+		}
+	}
+}
@@ -84,8 +84,9 @@ func (s *tonalityScorer) Score(ctx context.Context, in api.ScoreInputs) api.Scor
 		result.Score = 0
 		return result
 	}
+	recordGenerationConfig(result.Metadata, s.llm)
 
-	prompt := fmt.Sprintf(tonalityPromptTemplate, in.Input, in.Output)
+	prompt := fmt.Sprintf(tonalityPromptTemplate, promptContext(in), in.Output)
 
 	// Define schema for structured response
 	schema := map[string]interface{}{
@@ -146,9 +147,10 @@ func (s *tonalityScorer) Score(ctx context.Context, in api.ScoreInputs) api.Scor
 	}
 
 	// Use StructuredGenerate to get structured response
-	structuredResponse, err := s.llm.StructuredGenerate(ctx, prompt, schema)
+	structuredResponse, usage, err := structuredGenerate(ctx, s.llm, prompt, schema, in.Attachments)
+	recordUsage(result.Metadata, usage)
 	if err != nil {
-		return s.returnError(&result, fmt.Errorf("LLM generation failed: %v", err), nil)
+		return s.returnError(&result, fmt.Errorf("LLM generation failed: %w", err), nil)
 	}
 
 	// Extract choices from structured response
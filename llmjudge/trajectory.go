@@ -0,0 +1,98 @@
+package llmjudge
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// TrajectoryReasonablenessOptions configures the TrajectoryReasonableness scorer
+type TrajectoryReasonablenessOptions struct {
+	// Additional configuration options can be added here
+}
+
+// TrajectoryReasonableness returns a scorer that uses an LLM to judge whether an agent's Trajectory
+// was a reasonable path from the task in Input to the final Output
+// ExpectedTrajectory, when given, is shown to the judge as one acceptable path, not the only one
+func TrajectoryReasonableness(llm api.LLMGenerator, opts TrajectoryReasonablenessOptions) api.Scorer {
+	return &trajectoryReasonablenessScorer{
+		opts: opts,
+		llm:  llm,
+	}
+}
+
+type trajectoryReasonablenessScorer struct {
+	opts TrajectoryReasonablenessOptions
+	llm  api.LLMGenerator
+}
+
+const trajectoryPromptTemplate = `You are evaluating the path an AI agent took to complete a task. Here is the data:
+[BEGIN DATA]
+************
+[Task]: %s
+************
+[Trajectory]:
+%s
+************
+[Final Answer]: %s
+************
+[Reference Trajectory]:
+%s
+************
+[END DATA]
+
+A reasonable trajectory chooses appropriate tools with sensible arguments, makes use of what the observations return, avoids unnecessary or repeated steps, and leads logically to the final answer. A reference trajectory, if provided, is one acceptable path; other efficient paths are equally valid.
+Select one of the following options:
+(A) Every step is purposeful and the path leads directly to a well-supported final answer (EXCELLENT).
+(B) The path is sound with minor inefficiencies, such as one unnecessary step (GOOD).
+(C) The path reaches the answer but with several unnecessary, repeated or poorly chosen steps (FAIR).
+(D) The path is largely misguided, or the final answer is poorly supported by the steps taken (POOR).
+(E) The path does not make sense for the task, or the final answer contradicts the observations (FAIL).
+
+Provide your assessment with a choice (A, B, C, D, or E), a detailed explanation of your reasoning, and the steps that support it as evidence.`
+
+func (s *trajectoryReasonablenessScorer) Score(ctx context.Context, in api.ScoreInputs) api.Score {
+	result := api.Score{
+		Name:     "TrajectoryReasonableness",
+		Metadata: make(map[string]any),
+	}
+
+	if len(in.Trajectory) == 0 {
+		result.Error = api.ErrNoTrajectory
+		result.Score = 0
+		return result
+	}
+
+	if s.llm == nil {
+		result.Error = fmt.Errorf("LLM generator is required")
+		result.Score = 0
+		return result
+	}
+	recordGenerationConfig(result.Metadata, s.llm)
+
+	reference := "(not provided)"
+	if len(in.ExpectedTrajectory) > 0 {
+		reference = renderTrajectory(in.ExpectedTrajectory)
+	}
+	prompt := fmt.Sprintf(trajectoryPromptTemplate, orNotProvided(in.Input), renderTrajectory(in.Trajectory), orNotProvided(in.Output), reference)
+
+	judgement, err := judgeChoice(ctx, s.llm, prompt, "Trajectory reasonableness: (A) purposeful and direct (EXCELLENT), (B) minor inefficiencies (GOOD), (C) several unnecessary steps (FAIR), (D) largely misguided (POOR), (E) does not make sense (FAIL)", in.Attachments)
+	recordUsage(result.Metadata, judgement.usage)
+	if judgement.rawResponse != nil {
+		result.Metadata["raw_response"] = judgement.rawResponse
+	}
+	if err != nil {
+		result.Error = err
+		result.Score = 0
+		return result
+	}
+
+	result.Score = judgement.score
+	result.Metadata["choice"] = judgement.choice
+	result.Metadata["explanation"] = judgement.explanation
+	result.Metadata["evidence"] = judgement.evidence
+	result.Metadata["steps"] = len(in.Trajectory)
+
+	return result
+}
@@ -0,0 +1,121 @@
+package llmjudge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+func TestTrajectoryReasonableness_Unit(t *testing.T) {
+	ctx := context.Background()
+
+	trajectory := []api.Step{
+		{Type: api.StepThought, Content: "I should check the order status"},
+		{Type: api.StepToolCall, ToolCall: &api.ToolCall{Name: "lookup_order", Arguments: map[string]any{"id": "A1"}}},
+		{Type: api.StepObservation, Content: `{"status": "shipped"}`},
+	}
+
+	tests := []struct {
+		name        string
+		response    map[string]interface{}
+		llmErr      error
+		in          api.ScoreInputs
+		wantErr     error
+		wantAnyErr  bool
+		wantScore   float64
+		wantInPrmpt []string
+	}{
+		{
+			name:     "reasonable path",
+			response: map[string]interface{}{"choice": "A", "explanation": "direct", "evidence": []interface{}{"2. lookup_order"}},
+			in: api.ScoreInputs{
+				Input:      "Where is order A1?",
+				Trajectory: trajectory,
+				Output:     "Your order has shipped.",
+			},
+			wantScore: 1.0,
+			wantInPrmpt: []string{
+				"[Task]: Where is order A1?",
+				"1. [thought] I should check the order status",
+				`2. [tool_call] lookup_order({"id":"A1"})`,
+				`3. [observation] {"status": "shipped"}`,
+				"[Final Answer]: Your order has shipped.",
+				"[Reference Trajectory]:\n(not provided)",
+			},
+		},
+		{
+			name:     "reference trajectory shown",
+			response: map[string]interface{}{"choice": "C", "explanation": "detour"},
+			in: api.ScoreInputs{
+				Trajectory:         trajectory,
+				ExpectedTrajectory: []api.Step{{Type: api.StepToolCall, ToolCall: &api.ToolCall{Name: "track_package"}}},
+			},
+			wantScore:   0.5,
+			wantInPrmpt: []string{"[Reference Trajectory]:\n1. [tool_call] track_package({})", "[Final Answer]: (not provided)"},
+		},
+		{
+			name:    "no trajectory",
+			in:      api.ScoreInputs{Output: "answer"},
+			wantErr: api.ErrNoTrajectory,
+		},
+		{
+			name:       "llm error",
+			llmErr:     fmt.Errorf("API error"),
+			in:         api.ScoreInputs{Trajectory: trajectory},
+			wantAnyErr: true,
+		},
+		{
+			name:       "missing choice",
+			response:   map[string]interface{}{"explanation": "?"},
+			in:         api.ScoreInputs{Trajectory: trajectory},
+			wantAnyErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := &mockLLMGeneratorCapture{response: tt.response, err: tt.llmErr}
+			result := TrajectoryReasonableness(llm, TrajectoryReasonablenessOptions{}).Score(ctx, tt.in)
+
+			if result.Name != "TrajectoryReasonableness" {
+				t.Errorf("TrajectoryReasonableness.Score() name = %v", result.Name)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(result.Error, tt.wantErr) {
+					t.Errorf("TrajectoryReasonableness.Score() error = %v, want %v", result.Error, tt.wantErr)
+				}
+				return
+			}
+			if tt.wantAnyErr {
+				if result.Error == nil {
+					t.Error("TrajectoryReasonableness.Score() expected error but got none")
+				}
+				return
+			}
+			if result.Error != nil {
+				t.Fatalf("TrajectoryReasonableness.Score() unexpected error = %v", result.Error)
+			}
+			if result.Score != tt.wantScore {
+				t.Errorf("TrajectoryReasonableness.Score() score = %v, want %v", result.Score, tt.wantScore)
+			}
+			for _, want := range tt.wantInPrmpt {
+				if !strings.Contains(llm.prompt, want) {
+					t.Errorf("prompt missing %q\nprompt:\n%s", want, llm.prompt)
+				}
+			}
+		})
+	}
+}
+
+func TestTrajectoryReasonableness_NoLLM(t *testing.T) {
+	result := TrajectoryReasonableness(nil, TrajectoryReasonablenessOptions{}).Score(context.Background(), api.ScoreInputs{
+		Trajectory: []api.Step{{Type: api.StepThought, Content: "think"}},
+	})
+	if result.Error == nil {
+		t.Error("TrajectoryReasonableness.Score() expected error when LLM is nil")
+	}
+}
@@ -0,0 +1,69 @@
+package llmjudge
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// renderTranscript formats messages as one "[role]: content" block per turn, followed by any tool calls
+func renderTranscript(messages []api.Message) string {
+	var sb strings.Builder
+	for i, msg := range messages {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		role := msg.Role
+		if role == "" {
+			role = api.RoleUser
+		}
+		fmt.Fprintf(&sb, "[%s]: %s", role, msg.Content)
+		for _, call := range msg.ToolCalls {
+			fmt.Fprintf(&sb, "\n  -> tool call %s", formatToolCall(call))
+			if call.ID != "" {
+				fmt.Fprintf(&sb, " id=%s", call.ID)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// renderTrajectory formats agent steps as a numbered list, e.g. "2. [tool_call] search({"q":"x"})"
+func renderTrajectory(steps []api.Step) string {
+	var sb strings.Builder
+	for i, step := range steps {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		content := step.Content
+		if step.Type == api.StepToolCall && step.ToolCall != nil {
+			content = formatToolCall(*step.ToolCall)
+		}
+		fmt.Fprintf(&sb, "%d. [%s] %s", i+1, step.Type, content)
+	}
+	return sb.String()
+}
+
+// formatToolCall renders a tool call as name(arguments JSON)
+func formatToolCall(call api.ToolCall) string {
+	args, err := json.Marshal(call.Arguments)
+	if err != nil || call.Arguments == nil {
+		args = []byte("{}")
+	}
+	return fmt.Sprintf("%s(%s)", call.Name, args)
+}
+
+// promptContext returns the text placed in a judge prompt's question/context slot
+// Without Messages this is Input unchanged; otherwise the rendered conversation, preceded by Input if set
+func promptContext(in api.ScoreInputs) string {
+	if len(in.Messages) == 0 {
+		return in.Input
+	}
+	transcript := "Conversation so far:\n" + renderTranscript(in.Messages)
+	if in.Input == "" {
+		return transcript
+	}
+	return in.Input + "\n\n" + transcript
+}
@@ -0,0 +1,182 @@
+// Package local provides a deterministic, offline embedder for unit tests, CI and air-gapped runs
+package local
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// Default embedding settings
+const (
+	defaultDimensions = 512
+	defaultMinN       = 3
+	defaultMaxN       = 5
+)
+
+// Embedder embeds text as hashed word and character n-gram features, optionally weighted by TF-IDF
+// It needs no network access, and equal inputs always produce equal vectors.
+// Similarity reflects lexical overlap rather than meaning, so scores are not comparable with model embeddings
+type Embedder struct {
+	options EmbedderOptions
+
+	mu  sync.RWMutex
+	idf []float64
+}
+
+// EmbedderOptions configures an Embedder
+type EmbedderOptions struct {
+	dimensions int
+	minN       int
+	maxN       int
+}
+
+// WithDimensions sets the size of the hashed feature space (default: 512)
+// Larger spaces reduce collisions between unrelated features
+func WithDimensions(dimensions int) func(*EmbedderOptions) {
+	return func(opts *EmbedderOptions) {
+		opts.dimensions = dimensions
+	}
+}
+
+// WithNGramRange sets the range of character n-gram lengths (default: 3 to 5)
+// Use 0, 0 to embed whole words only. Otherwise lengths below 1 are raised to 1 and swapped bounds
+// are reordered, so WithNGramRange(0, 3) uses lengths 1 to 3
+func WithNGramRange(minN, maxN int) func(*EmbedderOptions) {
+	return func(opts *EmbedderOptions) {
+		if minN == 0 && maxN == 0 {
+			opts.minN, opts.maxN = 0, 0
+			return
+		}
+		minN, maxN = max(min(minN, maxN), 1), max(minN, maxN, 1)
+		opts.minN, opts.maxN = minN, maxN
+	}
+}
+
+// NewEmbedder creates a new offline embedder
+// opts: optional settings (e.g., WithDimensions(1024), WithNGramRange(2, 4))
+func NewEmbedder(opts ...func(*EmbedderOptions)) *Embedder {
+	e := &Embedder{
+		options: EmbedderOptions{
+			dimensions: defaultDimensions,
+			minN:       defaultMinN,
+			maxN:       defaultMaxN,
+		},
+	}
+	for _, opt := range opts {
+		opt(&e.options)
+	}
+	if e.options.dimensions <= 0 {
+		e.options.dimensions = defaultDimensions
+	}
+	return e
+}
+
+// Fit learns inverse document frequencies from corpus, so that features common across documents weigh less
+// Calling Fit again replaces the previously learned weights
+func (e *Embedder) Fit(corpus []string) {
+	df := make([]float64, e.options.dimensions)
+	for _, document := range corpus {
+		for index := range e.features(document) {
+			df[index]++
+		}
+	}
+
+	idf := make([]float64, e.options.dimensions)
+	n := float64(len(corpus))
+	for i := range idf {
+		// Smoothed IDF, as in scikit-learn: features seen in every document keep a weight of 1
+		idf[i] = math.Log((1+n)/(1+df[i])) + 1
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.idf = idf
+}
+
+// Embed implements Embedder.Embed
+func (e *Embedder) Embed(ctx context.Context, text string) ([]float64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	e.mu.RLock()
+	idf := e.idf
+	e.mu.RUnlock()
+
+	vector := make([]float64, e.options.dimensions)
+	for index, count := range e.features(text) {
+		// Sublinear term frequency keeps repeated words from dominating
+		weight := 1 + math.Log(count)
+		if idf != nil {
+			weight *= idf[index]
+		}
+		vector[index] = weight
+	}
+	normalize(vector)
+	return vector, nil
+}
+
+// EmbedBatch implements BatchEmbedder.EmbedBatch
+func (e *Embedder) EmbedBatch(ctx context.Context, texts []string) ([][]float64, error) {
+	embeddings := make([][]float64, len(texts))
+	for i, text := range texts {
+		embedding, err := e.Embed(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		embeddings[i] = embedding
+	}
+	return embeddings, nil
+}
+
+var wordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// features counts hashed features of text: lowercase words and character n-grams of space-padded words
+func (e *Embedder) features(text string) map[int]float64 {
+	counts := make(map[int]float64)
+	for _, word := range wordPattern.FindAllString(strings.ToLower(text), -1) {
+		counts[e.bucket("w:"+word)]++
+
+		padded := []rune(" " + word + " ")
+		for n := e.options.minN; n <= e.options.maxN && n > 0; n++ {
+			for i := 0; i+n <= len(padded); i++ {
+				counts[e.bucket("c:"+string(padded[i:i+n]))]++
+			}
+		}
+	}
+	return counts
+}
+
+// bucket hashes a feature into the feature space
+func (e *Embedder) bucket(feature string) int {
+	h := fnv.New64a()
+	h.Write([]byte(feature))
+	return int(h.Sum64() % uint64(e.options.dimensions))
+}
+
+// normalize scales v to unit length in place
+func normalize(v []float64) {
+	var sum float64
+	for _, x := range v {
+		sum += x * x
+	}
+	if sum == 0 {
+		return
+	}
+	norm := math.Sqrt(sum)
+	for i := range v {
+		v[i] /= norm
+	}
+}
+
+// Verify that Embedder implements goeval.Embedder and goeval.BatchEmbedder
+var (
+	_ api.Embedder      = (*Embedder)(nil)
+	_ api.BatchEmbedder = (*Embedder)(nil)
+)
@@ -0,0 +1,140 @@
+package local
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+	"github.com/datar-psa/goeval/embedding"
+)
+
+func cosine(a, b []float64) float64 {
+	var dot float64
+	for i := range a {
+		dot += a[i] * b[i]
+	}
+	return dot
+}
+
+func TestEmbedder_Embed(t *testing.T) {
+	ctx := context.Background()
+	e := NewEmbedder()
+
+	a, err := e.Embed(ctx, "What is the capital of France?")
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if len(a) != defaultDimensions {
+		t.Errorf("len = %d, want %d", len(a), defaultDimensions)
+	}
+	if norm := math.Sqrt(cosine(a, a)); math.Abs(norm-1) > 1e-9 {
+		t.Errorf("norm = %v, want 1", norm)
+	}
+
+	again, _ := NewEmbedder().Embed(ctx, "What is the capital of France?")
+	if !reflect.DeepEqual(a, again) {
+		t.Error("Embed() is not deterministic")
+	}
+
+	similar, _ := e.Embed(ctx, "what's the capital city of france")
+	different, _ := e.Embed(ctx, "How do I bake a cake?")
+	if cosine(a, similar) <= cosine(a, different) {
+		t.Errorf("similar = %v, different = %v, want similar > different", cosine(a, similar), cosine(a, different))
+	}
+
+	empty, _ := e.Embed(ctx, "")
+	for _, x := range empty {
+		if x != 0 {
+			t.Fatalf("Embed(\"\") = non-zero vector, want zero vector")
+		}
+	}
+}
+
+func TestEmbedder_Options(t *testing.T) {
+	e := NewEmbedder(WithDimensions(64), WithNGramRange(0, 0))
+	v, _ := e.Embed(context.Background(), "cats cats dogs")
+	if len(v) != 64 {
+		t.Fatalf("len = %d, want 64", len(v))
+	}
+	nonZero := 0
+	for _, x := range v {
+		if x != 0 {
+			nonZero++
+		}
+	}
+	if nonZero > 2 {
+		t.Errorf("non-zero features = %d, want at most 2 for word-only features", nonZero)
+	}
+}
+
+func TestWithNGramRange(t *testing.T) {
+	tests := []struct {
+		minN, maxN         int
+		wantMinN, wantMaxN int
+	}{
+		{minN: 0, maxN: 0, wantMinN: 0, wantMaxN: 0},
+		{minN: 0, maxN: 3, wantMinN: 1, wantMaxN: 3},
+		{minN: -2, maxN: 2, wantMinN: 1, wantMaxN: 2},
+		{minN: 4, maxN: 2, wantMinN: 2, wantMaxN: 4},
+		{minN: 2, maxN: 4, wantMinN: 2, wantMaxN: 4},
+	}
+
+	for _, tt := range tests {
+		var opts EmbedderOptions
+		WithNGramRange(tt.minN, tt.maxN)(&opts)
+		if opts.minN != tt.wantMinN || opts.maxN != tt.wantMaxN {
+			t.Errorf("WithNGramRange(%d, %d) = %d to %d, want %d to %d", tt.minN, tt.maxN, opts.minN, opts.maxN, tt.wantMinN, tt.wantMaxN)
+		}
+	}
+
+	// (0, 3) must still embed character n-grams rather than whole words only
+	ctx := context.Background()
+	ngrams, _ := NewEmbedder(WithNGramRange(0, 3)).Embed(ctx, "cats")
+	same, _ := NewEmbedder(WithNGramRange(1, 3)).Embed(ctx, "cats")
+	words, _ := NewEmbedder(WithNGramRange(0, 0)).Embed(ctx, "cats")
+	if !reflect.DeepEqual(ngrams, same) {
+		t.Error("WithNGramRange(0, 3) differs from WithNGramRange(1, 3)")
+	}
+	if reflect.DeepEqual(ngrams, words) {
+		t.Error("WithNGramRange(0, 3) embeds whole words only")
+	}
+}
+
+func TestEmbedder_Fit(t *testing.T) {
+	ctx := context.Background()
+	corpus := []string{
+		"the report covers revenue",
+		"the report covers costs",
+		"the report covers hiring",
+	}
+
+	plain := NewEmbedder(WithNGramRange(0, 0))
+	fitted := NewEmbedder(WithNGramRange(0, 0))
+	fitted.Fit(corpus)
+
+	// Sharing only common words should matter less once they are down-weighted
+	a, b := "the report covers revenue", "the report covers hiring"
+	pa, _ := plain.Embed(ctx, a)
+	pb, _ := plain.Embed(ctx, b)
+	fa, _ := fitted.Embed(ctx, a)
+	fb, _ := fitted.Embed(ctx, b)
+	if cosine(fa, fb) >= cosine(pa, pb) {
+		t.Errorf("fitted similarity = %v, plain = %v, want fitted < plain", cosine(fa, fb), cosine(pa, pb))
+	}
+}
+
+func TestEmbedder_EmbeddingSimilarity(t *testing.T) {
+	scorer := embedding.EmbeddingSimilarity(NewEmbedder(), embedding.EmbeddingSimilarityOptions{})
+
+	same := scorer.Score(context.Background(), api.ScoreInputs{Output: "Reset my password", Expected: "reset my password"})
+	if same.Error != nil || math.Abs(same.Score-1) > 1e-9 {
+		t.Errorf("identical text: Score = %v, error = %v, want 1", same.Score, same.Error)
+	}
+
+	batch, err := NewEmbedder().EmbedBatch(context.Background(), []string{"a", "b"})
+	if err != nil || len(batch) != 2 {
+		t.Errorf("EmbedBatch() = %d vectors, error = %v, want 2", len(batch), err)
+	}
+}
@@ -0,0 +1,81 @@
+package registry
+
+import (
+	"github.com/datar-psa/goeval/agent"
+	"github.com/datar-psa/goeval/api"
+	"github.com/datar-psa/goeval/embedding"
+	"github.com/datar-psa/goeval/heuristic"
+	"github.com/datar-psa/goeval/llmjudge"
+)
+
+func init() {
+	RegisterEnum(map[string]api.ReferenceAggregation{
+		"best":  api.AggregateBest,
+		"mean":  api.AggregateMean,
+		"worst": api.AggregateWorst,
+	})
+	RegisterEnum(map[string]heuristic.ROUGEVariant{
+		"rouge1": heuristic.ROUGE1,
+		"rouge2": heuristic.ROUGE2,
+		"rougeL": heuristic.ROUGEL,
+	})
+	RegisterEnum(map[string]heuristic.BLEUSmoothing{
+		"add_one": heuristic.BLEUSmoothingAddOne,
+		"epsilon": heuristic.BLEUSmoothingEpsilon,
+		"none":    heuristic.BLEUSmoothingNone,
+	})
+	RegisterEnum(map[string]heuristic.NumberSelection{
+		"last":  heuristic.NumberLast,
+		"first": heuristic.NumberFirst,
+		"any":   heuristic.NumberAny,
+	})
+	RegisterEnum(map[string]embedding.Granularity{
+		"token":    embedding.GranularityToken,
+		"phrase":   embedding.GranularityPhrase,
+		"sentence": embedding.GranularitySentence,
+	})
+	RegisterEnum(map[string]agent.TrajectoryMatchMode{
+		"exact":     agent.TrajectoryExact,
+		"in_order":  agent.TrajectoryInOrder,
+		"any_order": agent.TrajectoryAnyOrder,
+	})
+}
+
+// newBuiltinRegistry returns a registry with every scorer of this module
+func newBuiltinRegistry() *Registry {
+	r := New()
+
+	// LLM judges
+	r.MustRegister("Factuality", WithLLM(llmjudge.Factuality))
+	r.MustRegister("Tonality", WithLLM(llmjudge.Tonality))
+	r.MustRegister("GoalCompletion", WithLLM(llmjudge.GoalCompletion))
+	r.MustRegister("ConversationCoherence", WithLLM(llmjudge.ConversationCoherence))
+	r.MustRegister("UserFrustration", WithLLM(llmjudge.UserFrustration))
+	r.MustRegister("TrajectoryReasonableness", WithLLM(llmjudge.TrajectoryReasonableness))
+	r.MustRegister("Moderation", WithModeration(llmjudge.Moderation))
+
+	// Heuristics
+	r.MustRegister("ExactMatch", Options(heuristic.ExactMatch))
+	r.MustRegister("Regex", Options(heuristic.Regex))
+	r.MustRegister("NumericMatch", Options(heuristic.NumericMatch))
+	r.MustRegister("KeywordCoverage", Options(heuristic.KeywordCoverage))
+	r.MustRegister("ForbiddenTerms", Options(heuristic.ForbiddenTerms))
+	r.MustRegister("ROUGE", Options(heuristic.ROUGE))
+	r.MustRegister("BLEU", Options(heuristic.BLEU))
+	r.MustRegister("ChrF", Options(heuristic.ChrF))
+	r.MustRegister("JSONValid", Options(heuristic.JSONValid))
+	r.MustRegister("JSONSchema", Options(heuristic.JSONSchema))
+	r.MustRegister("JSONDiff", Options(heuristic.JSONDiff))
+
+	// Embeddings
+	r.MustRegister("EmbeddingSimilarity", WithEmbedder(embedding.EmbeddingSimilarity))
+	r.MustRegister("ListMatch", WithEmbedder(embedding.ListMatch))
+	r.MustRegister("BERTScore", WithEmbedder(embedding.BERTScore))
+
+	// Agents
+	r.MustRegister("ToolCallMatch", Options(agent.ToolCallMatch))
+	r.MustRegister("TrajectoryMatch", Options(agent.TrajectoryMatch))
+	r.MustRegister("RedundantSteps", Options(agent.RedundantSteps))
+
+	return r
+}
@@ -0,0 +1,229 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	enumMu sync.RWMutex
+	enums  = make(map[reflect.Type]map[string]reflect.Value)
+)
+
+// RegisterEnum registers the names options may use for values of an enum type, e.g. "mean" for api.AggregateMean
+// Names are matched case-insensitively; fields of a registered type only accept the registered names
+func RegisterEnum[T comparable](values map[string]T) {
+	named := make(map[string]reflect.Value, len(values))
+	for name, value := range values {
+		named[strings.ToLower(name)] = reflect.ValueOf(value)
+	}
+
+	enumMu.Lock()
+	defer enumMu.Unlock()
+	enums[reflect.TypeFor[T]()] = named
+}
+
+// Decode sets the fields of the struct pointed to by target from options
+// Keys match field names case-insensitively, with or without underscores ("case_insensitive" sets CaseInsensitive).
+// Unknown keys, values of the wrong type, unknown enum names and function fields are reported together in one error
+func Decode(options map[string]any, target any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("decode target must be a non-nil pointer to a struct, got %T", target)
+	}
+	if err := decodeStruct(options, v.Elem(), ""); err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+	return nil
+}
+
+func decodeStruct(options map[string]any, v reflect.Value, path string) error {
+	t := v.Type()
+	fields := make(map[string]int, t.NumField())
+	names := make([]string, 0, t.NumField())
+	for i := range t.NumField() {
+		if field := t.Field(i); field.IsExported() {
+			fields[normalizeKey(field.Name)] = i
+			names = append(names, field.Name)
+		}
+	}
+
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		index, ok := fields[normalizeKey(key)]
+		if !ok {
+			valid := "none"
+			if len(names) > 0 {
+				valid = strings.Join(names, ", ")
+			}
+			errs = append(errs, fmt.Errorf("%s: unknown field (valid: %s)", joinPath(path, key), valid))
+			continue
+		}
+		if err := decodeValue(options[key], v.Field(index), joinPath(path, t.Field(index).Name)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func decodeValue(raw any, v reflect.Value, path string) error {
+	if raw == nil {
+		return nil
+	}
+	t := v.Type()
+
+	enumMu.RLock()
+	named, isEnum := enums[t]
+	enumMu.RUnlock()
+	if isEnum {
+		name, ok := raw.(string)
+		value, found := named[strings.ToLower(name)]
+		if !ok || !found {
+			return fmt.Errorf("%s: invalid value %v (valid: %s)", path, raw, strings.Join(sortedKeys(named), ", "))
+		}
+		v.Set(value.Convert(t))
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		b, ok := raw.(bool)
+		if !ok {
+			return typeError(path, "a boolean", raw)
+		}
+		v.SetBool(b)
+	case reflect.String:
+		s, ok := raw.(string)
+		if !ok {
+			return typeError(path, "a string", raw)
+		}
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f, ok := toFloat(raw)
+		if !ok || f != math.Trunc(f) || v.OverflowInt(int64(f)) {
+			return typeError(path, "an integer", raw)
+		}
+		v.SetInt(int64(f))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f, ok := toFloat(raw)
+		if !ok || f < 0 || f != math.Trunc(f) || v.OverflowUint(uint64(f)) {
+			return typeError(path, "a non-negative integer", raw)
+		}
+		v.SetUint(uint64(f))
+	case reflect.Float32, reflect.Float64:
+		f, ok := toFloat(raw)
+		if !ok {
+			return typeError(path, "a number", raw)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		items, ok := raw.([]any)
+		if !ok {
+			return typeError(path, "a list", raw)
+		}
+		slice := reflect.MakeSlice(t, len(items), len(items))
+		var errs []error
+		for i, item := range items {
+			if err := decodeValue(item, slice.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if len(errs) > 0 {
+			return errors.Join(errs...)
+		}
+		v.Set(slice)
+	case reflect.Map:
+		entries, ok := raw.(map[string]any)
+		if !ok || t.Key().Kind() != reflect.String {
+			return typeError(path, "a map", raw)
+		}
+		m := reflect.MakeMapWithSize(t, len(entries))
+		var errs []error
+		for _, key := range sortedKeys(entries) {
+			value := reflect.New(t.Elem()).Elem()
+			if err := decodeValue(entries[key], value, joinPath(path, key)); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			m.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), value)
+		}
+		if len(errs) > 0 {
+			return errors.Join(errs...)
+		}
+		v.Set(m)
+	case reflect.Struct:
+		entries, ok := raw.(map[string]any)
+		if !ok {
+			return typeError(path, "a map", raw)
+		}
+		return decodeStruct(entries, v, path)
+	case reflect.Pointer:
+		value := reflect.New(t.Elem())
+		if err := decodeValue(raw, value.Elem(), path); err != nil {
+			return err
+		}
+		v.Set(value)
+	case reflect.Interface:
+		value := reflect.ValueOf(raw)
+		if !value.Type().AssignableTo(t) {
+			return typeError(path, t.String(), raw)
+		}
+		v.Set(value)
+	default:
+		return fmt.Errorf("%s: %s fields cannot be set from options", path, t.Kind())
+	}
+	return nil
+}
+
+// toFloat converts the numbers produced by JSON and YAML decoders
+func toFloat(raw any) (float64, bool) {
+	switch n := raw.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+func typeError(path, want string, raw any) error {
+	return fmt.Errorf("%s: expected %s, got %T", path, want, raw)
+}
+
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,122 @@
+package registry
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+	"github.com/datar-psa/goeval/embedding"
+	"github.com/datar-psa/goeval/heuristic"
+)
+
+type nestedOptions struct {
+	Name  string
+	Count int
+}
+
+type testOptions struct {
+	Enabled     bool
+	Label       string
+	MaxN        int
+	Weight      float64
+	Terms       []string
+	Schema      map[string]any
+	Nested      nestedOptions
+	Pointer     *nestedOptions
+	Aggregation api.ReferenceAggregation
+	Tokenizer   heuristic.Tokenizer
+	unexported  int
+}
+
+func TestDecode(t *testing.T) {
+	var got testOptions
+	err := Decode(map[string]any{
+		"enabled":     true,
+		"Label":       "x",
+		"max_n":       3,
+		"weight":      1, // YAML integers are accepted for float fields
+		"terms":       []any{"a", "b"},
+		"schema":      map[string]any{"type": "object"},
+		"nested":      map[string]any{"name": "n", "count": 2.0},
+		"pointer":     map[string]any{"count": 1},
+		"aggregation": "Mean",
+	}, &got)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	want := testOptions{
+		Enabled:     true,
+		Label:       "x",
+		MaxN:        3,
+		Weight:      1,
+		Terms:       []string{"a", "b"},
+		Schema:      map[string]any{"type": "object"},
+		Nested:      nestedOptions{Name: "n", Count: 2},
+		Pointer:     &nestedOptions{Count: 1},
+		Aggregation: api.AggregateMean,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		options map[string]any
+		want    []string
+	}{
+		{name: "unknown field", options: map[string]any{"enabeld": true}, want: []string{"enabeld: unknown field (valid: Enabled, Label"}},
+		{name: "unexported field", options: map[string]any{"unexported": 1}, want: []string{"unexported: unknown field"}},
+		{name: "wrong type", options: map[string]any{"enabled": "yes"}, want: []string{"Enabled: expected a boolean, got string"}},
+		{name: "fractional integer", options: map[string]any{"max_n": 2.5}, want: []string{"MaxN: expected an integer"}},
+		{name: "list element", options: map[string]any{"terms": []any{"a", 1}}, want: []string{"Terms[1]: expected a string, got int"}},
+		{name: "nested", options: map[string]any{"nested": map[string]any{"nmae": "x"}}, want: []string{"Nested.nmae: unknown field"}},
+		{name: "enum", options: map[string]any{"aggregation": "median"}, want: []string{"Aggregation: invalid value median (valid: best, mean, worst)"}},
+		{name: "enum number", options: map[string]any{"aggregation": 1}, want: []string{"Aggregation: invalid value 1"}},
+		{name: "function field", options: map[string]any{"tokenizer": "words"}, want: []string{"Tokenizer: func fields cannot be set from options"}},
+		{
+			name:    "all errors reported",
+			options: map[string]any{"enabled": 1, "label": 2},
+			want:    []string{"Enabled: expected a boolean", "Label: expected a string"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got testOptions
+			err := Decode(tt.options, &got)
+			if err == nil {
+				t.Fatal("Decode() error = nil, want error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Decode() error = %q, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+
+	if err := Decode(nil, testOptions{}); err == nil {
+		t.Error("Decode() into a non-pointer: error = nil, want error")
+	}
+}
+
+func TestDecode_BuiltinEnums(t *testing.T) {
+	var rouge heuristic.ROUGEOptions
+	if err := Decode(map[string]any{"variant": "rouge2"}, &rouge); err != nil || rouge.Variant != heuristic.ROUGE2 {
+		t.Errorf("ROUGE variant = %v, %v, want rouge2", rouge.Variant, err)
+	}
+
+	var bert embedding.BERTScoreOptions
+	if err := Decode(map[string]any{"granularity": "sentence"}, &bert); err != nil || bert.Granularity != embedding.GranularitySentence {
+		t.Errorf("BERTScore granularity = %v, %v, want sentence", bert.Granularity, err)
+	}
+
+	var similarity embedding.EmbeddingSimilarityOptions
+	if err := Decode(map[string]any{"calibration": map[string]any{"min": 0.6}}, &similarity); err != nil || similarity.Calibration == nil || similarity.Calibration.Min != 0.6 {
+		t.Errorf("EmbeddingSimilarity calibration = %v, %v, want min 0.6", similarity.Calibration, err)
+	}
+}
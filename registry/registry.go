@@ -0,0 +1,171 @@
+// Package registry maps scorer names to factories that build scorers from generic options maps,
+// so scorers can be declared in config files. Built-in scorers are registered by default and
+// third-party scorers can register themselves, typically from an init function
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+var (
+	// ErrUnknownScorer is returned when no factory is registered under a name
+	ErrUnknownScorer = errors.New("unknown scorer")
+	// ErrMissingProvider is returned when a scorer needs a provider that was not given
+	ErrMissingProvider = errors.New("missing provider")
+)
+
+// Providers are the dependencies factories build scorers with; unconfigured providers are nil
+type Providers struct {
+	LLM        api.LLMGenerator
+	Embedder   api.Embedder
+	Moderation api.ModerationProvider
+}
+
+// Factory builds a scorer from providers and an options map, e.g. decoded from YAML or JSON
+type Factory func(providers Providers, options map[string]any) (api.Scorer, error)
+
+// Registry maps scorer names to factories; it is safe for concurrent use
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]Factory
+}
+
+// New creates an empty registry; use Default for one with the built-in scorers
+func New() *Registry {
+	return &Registry{factories: make(map[string]Factory)}
+}
+
+// Register adds a factory under name; names are case-sensitive and cannot be registered twice
+func (r *Registry) Register(name string, factory Factory) error {
+	if name == "" {
+		return fmt.Errorf("scorer name is required")
+	}
+	if factory == nil {
+		return fmt.Errorf("scorer %q: factory is required", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.factories[name]; ok {
+		return fmt.Errorf("scorer %q is already registered", name)
+	}
+	r.factories[name] = factory
+	return nil
+}
+
+// MustRegister is like Register but panics on error
+func (r *Registry) MustRegister(name string, factory Factory) {
+	if err := r.Register(name, factory); err != nil {
+		panic(err)
+	}
+}
+
+// Build creates the scorer registered under name
+func (r *Registry) Build(name string, providers Providers, options map[string]any) (api.Scorer, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q (available: %s)", ErrUnknownScorer, name, strings.Join(r.Names(), ", "))
+	}
+	return factory(providers, options)
+}
+
+// Names returns the registered scorer names in alphabetical order
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// defaultRegistry holds the built-in scorers and package-level registrations
+var defaultRegistry = newBuiltinRegistry()
+
+// Default returns the registry used by the package-level functions
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Register adds a factory to the default registry
+func Register(name string, factory Factory) error {
+	return defaultRegistry.Register(name, factory)
+}
+
+// MustRegister adds a factory to the default registry, panicking on error
+func MustRegister(name string, factory Factory) {
+	defaultRegistry.MustRegister(name, factory)
+}
+
+// Build creates a scorer from the default registry
+func Build(name string, providers Providers, options map[string]any) (api.Scorer, error) {
+	return defaultRegistry.Build(name, providers, options)
+}
+
+// Names returns the scorer names in the default registry
+func Names() []string {
+	return defaultRegistry.Names()
+}
+
+// Options adapts a constructor taking only options, e.g. heuristic.ExactMatch
+func Options[O any](build func(O) api.Scorer) Factory {
+	return func(providers Providers, options map[string]any) (api.Scorer, error) {
+		var opts O
+		if err := Decode(options, &opts); err != nil {
+			return nil, err
+		}
+		return build(opts), nil
+	}
+}
+
+// WithLLM adapts a constructor taking an LLM generator and options, e.g. llmjudge.Factuality
+func WithLLM[O any](build func(api.LLMGenerator, O) api.Scorer) Factory {
+	return func(providers Providers, options map[string]any) (api.Scorer, error) {
+		if providers.LLM == nil {
+			return nil, fmt.Errorf("%w: requires an LLM generator", ErrMissingProvider)
+		}
+		var opts O
+		if err := Decode(options, &opts); err != nil {
+			return nil, err
+		}
+		return build(providers.LLM, opts), nil
+	}
+}
+
+// WithEmbedder adapts a constructor taking an embedder and options, e.g. embedding.EmbeddingSimilarity
+func WithEmbedder[O any](build func(api.Embedder, O) api.Scorer) Factory {
+	return func(providers Providers, options map[string]any) (api.Scorer, error) {
+		if providers.Embedder == nil {
+			return nil, fmt.Errorf("%w: requires an embedder", ErrMissingProvider)
+		}
+		var opts O
+		if err := Decode(options, &opts); err != nil {
+			return nil, err
+		}
+		return build(providers.Embedder, opts), nil
+	}
+}
+
+// WithModeration adapts a constructor taking a moderation provider and options, e.g. llmjudge.Moderation
+func WithModeration[O any](build func(api.ModerationProvider, O) api.Scorer) Factory {
+	return func(providers Providers, options map[string]any) (api.Scorer, error) {
+		if providers.Moderation == nil {
+			return nil, fmt.Errorf("%w: requires a moderation provider", ErrMissingProvider)
+		}
+		var opts O
+		if err := Decode(options, &opts); err != nil {
+			return nil, err
+		}
+		return build(providers.Moderation, opts), nil
+	}
+}
@@ -0,0 +1,106 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+type constantOptions struct {
+	Value float64
+}
+
+type constantScorer struct{ value float64 }
+
+func (s constantScorer) Score(ctx context.Context, in api.ScoreInputs) api.Score {
+	return api.Score{Name: "Constant", Score: s.value, Metadata: map[string]any{}}
+}
+
+type mockEmbedder struct{}
+
+func (mockEmbedder) Embed(ctx context.Context, text string) ([]float64, error) {
+	return []float64{1, 0}, nil
+}
+
+func TestRegistry_Register(t *testing.T) {
+	r := New()
+	factory := Options(func(opts constantOptions) api.Scorer { return constantScorer{value: opts.Value} })
+
+	if err := r.Register("Constant", factory); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := r.Register("Constant", factory); err == nil {
+		t.Error("Register() twice: error = nil, want error")
+	}
+	if err := r.Register("", factory); err == nil {
+		t.Error("Register() without name: error = nil, want error")
+	}
+	if err := r.Register("Nil", nil); err == nil {
+		t.Error("Register() without factory: error = nil, want error")
+	}
+
+	scorer, err := r.Build("Constant", Providers{}, map[string]any{"value": 0.5})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if got := scorer.Score(context.Background(), api.ScoreInputs{}).Score; got != 0.5 {
+		t.Errorf("Score = %v, want 0.5", got)
+	}
+
+	if _, err := r.Build("Missing", Providers{}, nil); !errors.Is(err, ErrUnknownScorer) || !strings.Contains(err.Error(), "available: Constant") {
+		t.Errorf("Build() unknown error = %v, want ErrUnknownScorer listing Constant", err)
+	}
+	if _, err := r.Build("Constant", Providers{}, map[string]any{"valeu": 1}); err == nil || !strings.Contains(err.Error(), "valeu: unknown field") {
+		t.Errorf("Build() with unknown option error = %v", err)
+	}
+}
+
+func TestDefault(t *testing.T) {
+	for _, name := range []string{"Factuality", "Tonality", "Moderation", "ExactMatch", "EmbeddingSimilarity", "BERTScore", "ToolCallMatch"} {
+		found := false
+		for _, registered := range Names() {
+			found = found || registered == name
+		}
+		if !found {
+			t.Errorf("Names() does not include %q", name)
+		}
+	}
+
+	scorer, err := Build("ExactMatch", Providers{}, map[string]any{"case_insensitive": true, "aggregation": "best"})
+	if err != nil {
+		t.Fatalf("Build(ExactMatch) error = %v", err)
+	}
+	if got := scorer.Score(context.Background(), api.ScoreInputs{Output: "Paris", Expected: "paris"}).Score; got != 1 {
+		t.Errorf("ExactMatch score = %v, want 1", got)
+	}
+
+	if _, err := Build("EmbeddingSimilarity", Providers{Embedder: mockEmbedder{}}, map[string]any{"calibration": map[string]any{"min": 0.5}}); err != nil {
+		t.Errorf("Build(EmbeddingSimilarity) error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Factuality", "requires an LLM generator"},
+		{"EmbeddingSimilarity", "requires an embedder"},
+		{"Moderation", "requires a moderation provider"},
+	}
+	for _, tt := range tests {
+		if _, err := Build(tt.name, Providers{}, nil); !errors.Is(err, ErrMissingProvider) || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Build(%s) error = %v, want ErrMissingProvider: %s", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestMustRegister_Panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustRegister() of a built-in name did not panic")
+		}
+	}()
+	MustRegister("ExactMatch", Options(func(opts constantOptions) api.Scorer { return constantScorer{} }))
+}
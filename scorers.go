@@ -1,8 +1,12 @@
 package goeval
 
 import (
+	"context"
+
 	language "cloud.google.com/go/language/apiv1"
+	"github.com/datar-psa/goeval/agent"
 	"github.com/datar-psa/goeval/api"
+	"github.com/datar-psa/goeval/cost"
 	"github.com/datar-psa/goeval/embedding"
 	"github.com/datar-psa/goeval/gemini"
 	"github.com/datar-psa/goeval/heuristic"
@@ -13,6 +17,9 @@ import (
 type Score = api.Score
 type ScoreInputs = api.ScoreInputs
 type Scorer = api.Scorer
+type Message = api.Message
+type ToolCall = api.ToolCall
+type Step = api.Step
 
 // LLMJudge wraps an LLM generator and exposes convenient constructors for LLM-as-a-judge scorers.
 // It allows creating scorers like Factuality and Tonality without passing the LLM each time.
@@ -55,9 +62,12 @@ func NewLLMJudge(opts ...func(*LLMJudgeOptions)) *LLMJudge {
 
 // GeminiOptions configures Gemini LLMJudge creation
 type GeminiOptions struct {
-	genaiClient *genai.Client
-	modelName   string
-	langClient  *language.Client
+	genaiClient      *genai.Client
+	modelName        string
+	langClient       *language.Client
+	generatorOptions []func(*gemini.GeneratorOptions)
+	embedderOptions  []func(*gemini.EmbedderOptions)
+	budget           *cost.Budget
 }
 
 // WithGenaiClient sets the Gemini client for the judge
@@ -81,6 +91,29 @@ func WithLanguageClient(langClient *language.Client) func(*GeminiOptions) {
 	}
 }
 
+// WithGeneratorOptions sets generation settings for the Gemini judge,
+// e.g. WithGeneratorOptions(gemini.WithTemperature(0), gemini.WithSeed(42)).
+func WithGeneratorOptions(generatorOptions ...func(*gemini.GeneratorOptions)) func(*GeminiOptions) {
+	return func(opts *GeminiOptions) {
+		opts.generatorOptions = append(opts.generatorOptions, generatorOptions...)
+	}
+}
+
+// WithEmbedderOptions sets embedding settings for the Gemini embedder,
+// e.g. WithEmbedderOptions(gemini.WithTaskType(gemini.TaskSemanticSimilarity), gemini.WithOutputDimensionality(256)).
+func WithEmbedderOptions(embedderOptions ...func(*gemini.EmbedderOptions)) func(*GeminiOptions) {
+	return func(opts *GeminiOptions) {
+		opts.embedderOptions = append(opts.embedderOptions, embedderOptions...)
+	}
+}
+
+// WithBudget guards every Gemini and moderation call with budget, failing scorers with ErrBudgetExceeded once it is spent.
+func WithBudget(budget *cost.Budget) func(*GeminiOptions) {
+	return func(opts *GeminiOptions) {
+		opts.budget = budget
+	}
+}
+
 // NewGeminiLLMJudge creates a Judge using Gemini client and model name.
 // Example model: "publishers/google/models/gemini-2.5-flash".
 func NewGeminiLLMJudge(opts ...func(*GeminiOptions)) *LLMJudge {
@@ -93,12 +126,20 @@ func NewGeminiLLMJudge(opts ...func(*GeminiOptions)) *LLMJudge {
 
 	// Only add LLM generator if genaiClient is provided
 	if options.genaiClient != nil && options.modelName != "" {
-		llmOptions = append(llmOptions, WithLLMGenerator(gemini.NewGenerator(options.genaiClient, options.modelName)))
+		var llm api.LLMGenerator = gemini.NewGenerator(options.genaiClient, options.modelName, options.generatorOptions...)
+		if options.budget != nil {
+			llm = options.budget.Generator(llm)
+		}
+		llmOptions = append(llmOptions, WithLLMGenerator(llm))
 	}
 
 	// Only add moderation provider if langClient is provided
 	if options.langClient != nil {
-		llmOptions = append(llmOptions, WithModerationProvider(gemini.NewGoogleLanguageProvider(options.langClient)))
+		var provider api.ModerationProvider = gemini.NewGoogleLanguageProvider(options.langClient)
+		if options.budget != nil {
+			provider = options.budget.ModerationProvider(provider)
+		}
+		llmOptions = append(llmOptions, WithModerationProvider(provider))
 	}
 
 	return NewLLMJudge(llmOptions...)
@@ -125,6 +166,34 @@ func (j *LLMJudge) Moderation(opts ModerationOptions) api.Scorer {
 	return llmjudge.Moderation(j.moderation, opts)
 }
 
+type GoalCompletionOptions = llmjudge.GoalCompletionOptions
+
+// GoalCompletion returns a scorer that judges whether the user's goal was accomplished over a conversation.
+func (j *LLMJudge) GoalCompletion(opts GoalCompletionOptions) api.Scorer {
+	return llmjudge.GoalCompletion(j.llm, opts)
+}
+
+type ConversationCoherenceOptions = llmjudge.ConversationCoherenceOptions
+
+// ConversationCoherence returns a scorer that judges consistency across the turns of a conversation.
+func (j *LLMJudge) ConversationCoherence(opts ConversationCoherenceOptions) api.Scorer {
+	return llmjudge.ConversationCoherence(j.llm, opts)
+}
+
+type UserFrustrationOptions = llmjudge.UserFrustrationOptions
+
+// UserFrustration returns a scorer that detects user frustration in a conversation (1.0 means none).
+func (j *LLMJudge) UserFrustration(opts UserFrustrationOptions) api.Scorer {
+	return llmjudge.UserFrustration(j.llm, opts)
+}
+
+type TrajectoryReasonablenessOptions = llmjudge.TrajectoryReasonablenessOptions
+
+// TrajectoryReasonableness returns a scorer that judges whether an agent trajectory was a reasonable path to the output.
+func (j *LLMJudge) TrajectoryReasonableness(opts TrajectoryReasonablenessOptions) api.Scorer {
+	return llmjudge.TrajectoryReasonableness(j.llm, opts)
+}
+
 // Embedding wraps an embedder and exposes convenient constructors for embedding-based scorers.
 type Embedding struct{ embedder api.Embedder }
 
@@ -161,7 +230,11 @@ func NewGeminiEmbedding(opts ...func(*GeminiOptions)) *Embedding {
 
 	// Only add embedder if genaiClient and modelName are provided
 	if options.genaiClient != nil && options.modelName != "" {
-		embeddingOptions = append(embeddingOptions, WithEmbedder(gemini.NewEmbedder(options.genaiClient, options.modelName)))
+		var embedder api.Embedder = gemini.NewEmbedder(options.genaiClient, options.modelName, options.embedderOptions...)
+		if options.budget != nil {
+			embedder = options.budget.Embedder(embedder)
+		}
+		embeddingOptions = append(embeddingOptions, WithEmbedder(embedder))
 	}
 
 	return NewEmbedding(embeddingOptions...)
@@ -174,6 +247,19 @@ func (e *Embedding) Similarity(opts EmbeddingSimilarityOptions) api.Scorer {
 	return embedding.EmbeddingSimilarity(e.embedder, opts)
 }
 
+type Calibration = embedding.Calibration
+type CalibrationPair = embedding.CalibrationPair
+
+// Baseline returns a Calibration that rescales cosine similarity from [baseline, 1] to [0, 1].
+func Baseline(baseline float64) Calibration {
+	return embedding.Baseline(baseline)
+}
+
+// Calibrate learns a Calibration for the embedder from labeled similar and dissimilar pairs.
+func (e *Embedding) Calibrate(ctx context.Context, pairs []CalibrationPair) (Calibration, error) {
+	return embedding.Calibrate(ctx, e.embedder, pairs)
+}
+
 // Heuristic exposes convenient constructors for heuristic scorers.
 type Heuristic struct{}
 
@@ -188,3 +274,148 @@ type ExactMatchOptions = heuristic.ExactMatchOptions
 func (h *Heuristic) ExactMatch(opts ExactMatchOptions) api.Scorer {
 	return heuristic.ExactMatch(opts)
 }
+
+type BLEUOptions = heuristic.BLEUOptions
+
+// BLEU returns a scorer that computes sentence-level BLEU against the references.
+func (h *Heuristic) BLEU(opts BLEUOptions) api.Scorer {
+	return heuristic.BLEU(opts)
+}
+
+type ROUGEOptions = heuristic.ROUGEOptions
+
+// ROUGE returns a scorer that computes ROUGE-1/2/L F1 against the references.
+func (h *Heuristic) ROUGE(opts ROUGEOptions) api.Scorer {
+	return heuristic.ROUGE(opts)
+}
+
+type ChrFOptions = heuristic.ChrFOptions
+
+// ChrF returns a scorer that computes the character n-gram F-score against the references.
+func (h *Heuristic) ChrF(opts ChrFOptions) api.Scorer {
+	return heuristic.ChrF(opts)
+}
+
+type JSONValidOptions = heuristic.JSONValidOptions
+
+// JSONValid returns a scorer that checks if the output parses as JSON.
+func (h *Heuristic) JSONValid(opts JSONValidOptions) api.Scorer {
+	return heuristic.JSONValid(opts)
+}
+
+type JSONSchemaOptions = heuristic.JSONSchemaOptions
+
+// JSONSchema returns a scorer that validates the output against a JSON Schema.
+func (h *Heuristic) JSONSchema(opts JSONSchemaOptions) api.Scorer {
+	return heuristic.JSONSchema(opts)
+}
+
+type JSONDiffOptions = heuristic.JSONDiffOptions
+
+// JSONDiff returns a scorer that structurally compares output JSON to expected JSON with per-path partial credit.
+func (h *Heuristic) JSONDiff(opts JSONDiffOptions) api.Scorer {
+	return heuristic.JSONDiff(opts)
+}
+
+type NumericMatchOptions = heuristic.NumericMatchOptions
+
+// NumericMatch returns a scorer that extracts and compares numbers with configurable tolerance.
+func (h *Heuristic) NumericMatch(opts NumericMatchOptions) api.Scorer {
+	return heuristic.NumericMatch(opts)
+}
+
+type RegexOptions = heuristic.RegexOptions
+
+// Regex returns a scorer that checks the output against a regular expression (match or no-match).
+func (h *Heuristic) Regex(opts RegexOptions) api.Scorer {
+	return heuristic.Regex(opts)
+}
+
+type KeywordCoverageOptions = heuristic.KeywordCoverageOptions
+
+// KeywordCoverage returns a scorer that measures the fraction of required keywords present in the output.
+func (h *Heuristic) KeywordCoverage(opts KeywordCoverageOptions) api.Scorer {
+	return heuristic.KeywordCoverage(opts)
+}
+
+type ForbiddenTermsOptions = heuristic.ForbiddenTermsOptions
+
+// ForbiddenTerms returns a scorer that checks the output contains none of the forbidden terms or patterns.
+func (h *Heuristic) ForbiddenTerms(opts ForbiddenTermsOptions) api.Scorer {
+	return heuristic.ForbiddenTerms(opts)
+}
+
+type ListMatchOptions = embedding.ListMatchOptions
+
+// ListMatch returns a scorer that matches list items semantically, ignoring order and wording.
+func (e *Embedding) ListMatch(opts ListMatchOptions) api.Scorer {
+	return embedding.ListMatch(e.embedder, opts)
+}
+
+type BERTScoreOptions = embedding.BERTScoreOptions
+type Granularity = embedding.Granularity
+type UnitMatch = embedding.UnitMatch
+
+const (
+	GranularityToken    = embedding.GranularityToken
+	GranularityPhrase   = embedding.GranularityPhrase
+	GranularitySentence = embedding.GranularitySentence
+)
+
+// BERTScore returns a scorer that aligns tokens, phrases or sentences by embedding similarity and reports precision, recall and F1.
+func (e *Embedding) BERTScore(opts BERTScoreOptions) api.Scorer {
+	return embedding.BERTScore(e.embedder, opts)
+}
+
+// Agent exposes convenient constructors for scorers of tool-using agents.
+type Agent struct{}
+
+// NewAgent creates a new Agent.
+func NewAgent() *Agent {
+	return &Agent{}
+}
+
+type ToolCallMatchOptions = agent.ToolCallMatchOptions
+type ToolCallResult = agent.ToolCallResult
+type ArgumentComparator = agent.ArgumentComparator
+
+// ToolCallMatch returns a scorer that compares produced tool calls against expected tool calls.
+func (a *Agent) ToolCallMatch(opts ToolCallMatchOptions) api.Scorer {
+	return agent.ToolCallMatch(opts)
+}
+
+type TrajectoryMatchOptions = agent.TrajectoryMatchOptions
+type TrajectoryMatchMode = agent.TrajectoryMatchMode
+
+const (
+	TrajectoryExact    = agent.TrajectoryExact
+	TrajectoryInOrder  = agent.TrajectoryInOrder
+	TrajectoryAnyOrder = agent.TrajectoryAnyOrder
+)
+
+// TrajectoryMatch returns a scorer that compares the tool calls of a trajectory against a reference trajectory.
+func (a *Agent) TrajectoryMatch(opts TrajectoryMatchOptions) api.Scorer {
+	return agent.TrajectoryMatch(opts)
+}
+
+type RedundantStepsOptions = agent.RedundantStepsOptions
+
+// RedundantSteps returns a scorer that penalizes repeated tool calls in a trajectory.
+func (a *Agent) RedundantSteps(opts RedundantStepsOptions) api.Scorer {
+	return agent.RedundantSteps(opts)
+}
+
+// ExactArgument returns a comparator requiring JSON-equal argument values.
+func ExactArgument() ArgumentComparator {
+	return agent.ExactArgument()
+}
+
+// NumericArgument returns a comparator accepting numeric arguments within an absolute tolerance.
+func NumericArgument(tolerance float64) ArgumentComparator {
+	return agent.NumericArgument(tolerance)
+}
+
+// EmbeddingArgument returns a comparator scoring arguments by embedding cosine similarity, with partial credit below threshold.
+func EmbeddingArgument(embedder api.Embedder, threshold float64) ArgumentComparator {
+	return agent.EmbeddingArgument(embedder, threshold)
+}
@@ -0,0 +1,275 @@
+// Package selfhosted provides embedders for self-hosted embedding servers such as
+// Hugging Face text-embeddings-inference and the llama.cpp server
+package selfhosted
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// Default request settings
+const (
+	defaultMaxBatchSize = 32
+	maxErrorBodyBytes   = 512
+)
+
+// Pooling selects how token embeddings are combined into one vector per text
+type Pooling int
+
+const (
+	// PoolingServer uses the vector pooled by the server with its configured pooling (default)
+	PoolingServer Pooling = iota
+	// PoolingMean averages the token embeddings
+	PoolingMean
+	// PoolingCLS takes the first token embedding
+	PoolingCLS
+	// PoolingLast takes the last token embedding, as used by decoder-based embedding models
+	PoolingLast
+)
+
+// Embedder implements the Embedder, BatchEmbedder and BatchRequestCounter interfaces over the HTTP API of a self-hosted embedding server
+type Embedder struct {
+	baseURL string
+	backend backend
+	options EmbedderOptions
+}
+
+// EmbedderOptions configures an Embedder
+type EmbedderOptions struct {
+	httpClient   *http.Client
+	headers      http.Header
+	maxBatchSize int
+	pooling      Pooling
+	normalize    bool
+	truncate     bool
+}
+
+// WithHTTPClient sets the HTTP client used for requests (default: http.DefaultClient)
+func WithHTTPClient(client *http.Client) func(*EmbedderOptions) {
+	return func(opts *EmbedderOptions) {
+		opts.httpClient = client
+	}
+}
+
+// WithHeader adds a header sent with every request, e.g. WithHeader("Authorization", "Bearer "+token)
+func WithHeader(key, value string) func(*EmbedderOptions) {
+	return func(opts *EmbedderOptions) {
+		opts.headers.Add(key, value)
+	}
+}
+
+// WithMaxBatchSize sets the maximum number of texts sent in one request (default: 32)
+// It should not exceed the server's client batch limit
+func WithMaxBatchSize(size int) func(*EmbedderOptions) {
+	return func(opts *EmbedderOptions) {
+		opts.maxBatchSize = size
+	}
+}
+
+// WithPooling pools token embeddings on the client instead of using the server pooling
+// The server must be able to return token embeddings: text-embeddings-inference serves them from /embed_all,
+// llama.cpp when started with --pooling none
+func WithPooling(pooling Pooling) func(*EmbedderOptions) {
+	return func(opts *EmbedderOptions) {
+		opts.pooling = pooling
+	}
+}
+
+// WithNormalize sets whether returned vectors are scaled to unit length (default: true)
+// Disabling it breaks the api.Embedder contract and is meant for inspecting raw vectors
+func WithNormalize(normalize bool) func(*EmbedderOptions) {
+	return func(opts *EmbedderOptions) {
+		opts.normalize = normalize
+	}
+}
+
+// WithTruncate asks the server to truncate inputs longer than the model's maximum sequence length
+// Supported by text-embeddings-inference; llama.cpp rejects or truncates according to its own settings
+func WithTruncate(truncate bool) func(*EmbedderOptions) {
+	return func(opts *EmbedderOptions) {
+		opts.truncate = truncate
+	}
+}
+
+// backend adapts a server API
+type backend interface {
+	// request returns the endpoint path and JSON request body for texts
+	request(texts []string, opts EmbedderOptions) (string, any)
+	// decode parses the response into token embeddings per text; pooled vectors are returned as a single row
+	decode(data []byte) ([][][]float64, error)
+}
+
+func newEmbedder(baseURL string, backend backend, opts []func(*EmbedderOptions)) *Embedder {
+	e := &Embedder{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		backend: backend,
+		options: EmbedderOptions{
+			httpClient:   http.DefaultClient,
+			headers:      make(http.Header),
+			maxBatchSize: defaultMaxBatchSize,
+			normalize:    true,
+		},
+	}
+	for _, opt := range opts {
+		opt(&e.options)
+	}
+	if e.options.maxBatchSize <= 0 {
+		e.options.maxBatchSize = defaultMaxBatchSize
+	}
+	return e
+}
+
+// Embed implements Embedder.Embed
+func (e *Embedder) Embed(ctx context.Context, text string) ([]float64, error) {
+	embeddings, err := e.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// EmbedBatch implements BatchEmbedder.EmbedBatch
+// Texts are sent in requests of at most the configured batch size
+func (e *Embedder) EmbedBatch(ctx context.Context, texts []string) ([][]float64, error) {
+	embeddings := make([][]float64, 0, len(texts))
+	for start := 0; start < len(texts); start += e.options.maxBatchSize {
+		end := min(start+e.options.maxBatchSize, len(texts))
+		batchEmbeddings, err := e.embed(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		embeddings = append(embeddings, batchEmbeddings...)
+	}
+	return embeddings, nil
+}
+
+// BatchRequests implements BatchRequestCounter.BatchRequests
+func (e *Embedder) BatchRequests(texts []string) int {
+	return (len(texts) + e.options.maxBatchSize - 1) / e.options.maxBatchSize
+}
+
+// embed sends one request for texts and pools and normalizes the results
+func (e *Embedder) embed(ctx context.Context, texts []string) ([][]float64, error) {
+	path, body := e.backend.request(texts, e.options)
+	data, err := e.post(ctx, path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate embedding: %w", err)
+	}
+
+	tokens, err := e.backend.decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse embedding response: %w", err)
+	}
+	if len(tokens) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(tokens))
+	}
+
+	embeddings := make([][]float64, len(texts))
+	for i, rows := range tokens {
+		embedding, err := pool(rows, e.options.pooling)
+		if err != nil {
+			return nil, err
+		}
+		if e.options.normalize {
+			normalize(embedding)
+		}
+		embeddings[i] = embedding
+	}
+	return embeddings, nil
+}
+
+// post sends body as JSON to path and returns the response body of a successful request
+func (e *Embedder) post(ctx context.Context, path string, body any) ([]byte, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range e.options.headers {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.options.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		message := strings.TrimSpace(string(data))
+		if len(message) > maxErrorBodyBytes {
+			message = message[:maxErrorBodyBytes] + "..."
+		}
+		return nil, fmt.Errorf("server returned %s: %s", resp.Status, message)
+	}
+	return data, nil
+}
+
+// pool combines token embeddings into one vector
+func pool(rows [][]float64, pooling Pooling) ([]float64, error) {
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		return nil, fmt.Errorf("empty embedding vector")
+	}
+
+	switch pooling {
+	case PoolingMean:
+		mean := make([]float64, len(rows[0]))
+		for _, row := range rows {
+			if len(row) != len(mean) {
+				return nil, fmt.Errorf("token embeddings have different dimensions: %d and %d", len(mean), len(row))
+			}
+			for i, x := range row {
+				mean[i] += x / float64(len(rows))
+			}
+		}
+		return mean, nil
+	case PoolingCLS:
+		return append([]float64(nil), rows[0]...), nil
+	case PoolingLast:
+		return append([]float64(nil), rows[len(rows)-1]...), nil
+	default:
+		if len(rows) != 1 {
+			return nil, fmt.Errorf("server returned %d token embeddings instead of a pooled vector; set WithPooling", len(rows))
+		}
+		return rows[0], nil
+	}
+}
+
+// normalize scales v to unit length in place
+func normalize(v []float64) {
+	var sum float64
+	for _, x := range v {
+		sum += x * x
+	}
+	if sum == 0 {
+		return
+	}
+	norm := math.Sqrt(sum)
+	for i := range v {
+		v[i] /= norm
+	}
+}
+
+// Verify that Embedder implements goeval.Embedder, goeval.BatchEmbedder and goeval.BatchRequestCounter
+var (
+	_ api.Embedder            = (*Embedder)(nil)
+	_ api.BatchEmbedder       = (*Embedder)(nil)
+	_ api.BatchRequestCounter = (*Embedder)(nil)
+)
@@ -0,0 +1,189 @@
+package selfhosted
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeServer records request bodies and answers with respond(path, body)
+type fakeServer struct {
+	*httptest.Server
+	paths    []string
+	requests []map[string]any
+	headers  []http.Header
+}
+
+func newFakeServer(t *testing.T, respond func(path string, body map[string]any) (int, any)) *fakeServer {
+	t.Helper()
+	s := &fakeServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		s.paths = append(s.paths, r.URL.Path)
+		s.requests = append(s.requests, body)
+		s.headers = append(s.headers, r.Header.Clone())
+
+		status, response := respond(r.URL.Path, body)
+		w.WriteHeader(status)
+		if text, ok := response.(string); ok {
+			w.Write([]byte(text))
+			return
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func textsOf(values any) []string {
+	var texts []string
+	for _, v := range values.([]any) {
+		texts = append(texts, v.(string))
+	}
+	return texts
+}
+
+// vectorFor returns an unnormalized vector that identifies text by its length
+func vectorFor(text string) []float64 {
+	return []float64{float64(len(text)), 0, 0}
+}
+
+func TestTEIEmbedder(t *testing.T) {
+	server := newFakeServer(t, func(path string, body map[string]any) (int, any) {
+		var vectors [][]float64
+		for _, text := range textsOf(body["inputs"]) {
+			vectors = append(vectors, vectorFor(text))
+		}
+		return http.StatusOK, vectors
+	})
+
+	e := NewTEIEmbedder(server.URL+"/", WithMaxBatchSize(2), WithTruncate(true), WithHeader("Authorization", "Bearer token"))
+	got, err := e.EmbedBatch(context.Background(), []string{"a", "bb", "ccc"})
+	if err != nil {
+		t.Fatalf("EmbedBatch() error = %v", err)
+	}
+
+	want := [][]float64{{1, 0, 0}, {1, 0, 0}, {1, 0, 0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EmbedBatch() = %v, want normalized %v", got, want)
+	}
+	if !reflect.DeepEqual(server.paths, []string{"/embed", "/embed"}) {
+		t.Errorf("paths = %v, want two /embed requests", server.paths)
+	}
+	if got := e.BatchRequests([]string{"a", "bb", "ccc"}); got != len(server.paths) {
+		t.Errorf("BatchRequests() = %d, want %d", got, len(server.paths))
+	}
+	if inputs := textsOf(server.requests[0]["inputs"]); !reflect.DeepEqual(inputs, []string{"a", "bb"}) {
+		t.Errorf("first batch = %v, want [a bb]", inputs)
+	}
+	if server.requests[0]["truncate"] != true || server.requests[0]["normalize"] != false {
+		t.Errorf("request = %v, want truncate true and normalize false", server.requests[0])
+	}
+	if got := server.headers[0].Get("Authorization"); got != "Bearer token" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer token")
+	}
+}
+
+func TestTEIEmbedder_ClientPooling(t *testing.T) {
+	server := newFakeServer(t, func(path string, body map[string]any) (int, any) {
+		if path != "/embed_all" {
+			return http.StatusNotFound, "unexpected path"
+		}
+		return http.StatusOK, [][][]float64{{{1, 0}, {0, 1}, {0, 3}}}
+	})
+
+	tests := []struct {
+		pooling Pooling
+		want    []float64
+	}{
+		{PoolingMean, []float64{1.0 / 3, 4.0 / 3}},
+		{PoolingCLS, []float64{1, 0}},
+		{PoolingLast, []float64{0, 3}},
+	}
+	for _, tt := range tests {
+		got, err := NewTEIEmbedder(server.URL, WithPooling(tt.pooling), WithNormalize(false)).Embed(context.Background(), "text")
+		if err != nil {
+			t.Fatalf("pooling %d: Embed() error = %v", tt.pooling, err)
+		}
+		for i := range tt.want {
+			if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+				t.Errorf("pooling %d: Embed() = %v, want %v", tt.pooling, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestLlamaCppEmbedder(t *testing.T) {
+	server := newFakeServer(t, func(path string, body map[string]any) (int, any) {
+		// Answer out of order, as the server may
+		texts := textsOf(body["content"])
+		var results []llamaCppEmbedding
+		for i := len(texts) - 1; i >= 0; i-- {
+			results = append(results, llamaCppEmbedding{Index: i, Embedding: [][]float64{{0, float64(len(texts[i]))}}})
+		}
+		return http.StatusOK, results
+	})
+
+	got, err := NewLlamaCppEmbedder(server.URL, WithNormalize(false)).EmbedBatch(context.Background(), []string{"a", "bb"})
+	if err != nil {
+		t.Fatalf("EmbedBatch() error = %v", err)
+	}
+	if want := [][]float64{{0, 1}, {0, 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("EmbedBatch() = %v, want %v", got, want)
+	}
+	if server.paths[0] != "/embedding" {
+		t.Errorf("path = %q, want /embedding", server.paths[0])
+	}
+}
+
+func TestLlamaCppEmbedder_TokenEmbeddings(t *testing.T) {
+	server := newFakeServer(t, func(path string, body map[string]any) (int, any) {
+		return http.StatusOK, []llamaCppEmbedding{{Index: 0, Embedding: [][]float64{{2, 0}, {0, 2}}}}
+	})
+
+	if _, err := NewLlamaCppEmbedder(server.URL).Embed(context.Background(), "text"); err == nil || !strings.Contains(err.Error(), "WithPooling") {
+		t.Errorf("Embed() error = %v, want error suggesting WithPooling", err)
+	}
+
+	got, err := NewLlamaCppEmbedder(server.URL, WithPooling(PoolingMean)).Embed(context.Background(), "text")
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if want := []float64{math.Sqrt2 / 2, math.Sqrt2 / 2}; math.Abs(got[0]-want[0]) > 1e-9 || math.Abs(got[1]-want[1]) > 1e-9 {
+		t.Errorf("Embed() = %v, want %v", got, want)
+	}
+}
+
+func TestEmbedder_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		response any
+		wantErr  string
+	}{
+		{name: "server error", status: http.StatusRequestEntityTooLarge, response: "batch size 64 > maximum allowed batch size 32", wantErr: "413"},
+		{name: "wrong count", status: http.StatusOK, response: [][]float64{{1}, {1}}, wantErr: "expected 1 embeddings, got 2"},
+		{name: "empty vector", status: http.StatusOK, response: [][]float64{{}}, wantErr: "empty embedding vector"},
+		{name: "invalid JSON", status: http.StatusOK, response: "not json", wantErr: "failed to parse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeServer(t, func(path string, body map[string]any) (int, any) {
+				return tt.status, tt.response
+			})
+			if _, err := NewTEIEmbedder(server.URL).Embed(context.Background(), "text"); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Embed() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package selfhosted
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// NewLlamaCppEmbedder creates an embedder for a llama.cpp server started with --embeddings
+// baseURL: the server address (e.g., "http://localhost:8080")
+// opts: optional settings (e.g., WithMaxBatchSize(8))
+// With --pooling none the server returns token embeddings, which must be pooled with WithPooling
+func NewLlamaCppEmbedder(baseURL string, opts ...func(*EmbedderOptions)) *Embedder {
+	return newEmbedder(baseURL, llamaCppBackend{}, opts)
+}
+
+type llamaCppBackend struct{}
+
+type llamaCppRequest struct {
+	Content []string `json:"content"`
+}
+
+type llamaCppEmbedding struct {
+	Index     int         `json:"index"`
+	Embedding [][]float64 `json:"embedding"`
+}
+
+func (llamaCppBackend) request(texts []string, opts EmbedderOptions) (string, any) {
+	return "/embedding", llamaCppRequest{Content: texts}
+}
+
+func (llamaCppBackend) decode(data []byte) ([][][]float64, error) {
+	var results []llamaCppEmbedding
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, err
+	}
+
+	// Results carry the input index and are not guaranteed to be in order
+	tokens := make([][][]float64, len(results))
+	for _, result := range results {
+		if result.Index < 0 || result.Index >= len(results) || tokens[result.Index] != nil {
+			return nil, fmt.Errorf("invalid embedding index %d", result.Index)
+		}
+		tokens[result.Index] = result.Embedding
+	}
+	return tokens, nil
+}
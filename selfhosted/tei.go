@@ -0,0 +1,49 @@
+package selfhosted
+
+import (
+	"encoding/json"
+)
+
+// NewTEIEmbedder creates an embedder for a Hugging Face text-embeddings-inference server
+// baseURL: the server address (e.g., "http://localhost:8080")
+// opts: optional settings (e.g., WithMaxBatchSize(64), WithTruncate(true))
+// Pooled vectors come from /embed; with WithPooling, token embeddings come from /embed_all
+func NewTEIEmbedder(baseURL string, opts ...func(*EmbedderOptions)) *Embedder {
+	return newEmbedder(baseURL, teiBackend{}, opts)
+}
+
+type teiBackend struct{}
+
+type teiRequest struct {
+	Inputs    []string `json:"inputs"`
+	Truncate  bool     `json:"truncate,omitempty"`
+	Normalize *bool    `json:"normalize,omitempty"`
+}
+
+func (teiBackend) request(texts []string, opts EmbedderOptions) (string, any) {
+	if opts.pooling != PoolingServer {
+		return "/embed_all", teiRequest{Inputs: texts, Truncate: opts.truncate}
+	}
+	// Vectors are normalized on the client, so the server can return them as they are
+	normalize := false
+	return "/embed", teiRequest{Inputs: texts, Truncate: opts.truncate, Normalize: &normalize}
+}
+
+func (teiBackend) decode(data []byte) ([][][]float64, error) {
+	// /embed_all returns token embeddings per input
+	var tokens [][][]float64
+	if err := json.Unmarshal(data, &tokens); err == nil {
+		return tokens, nil
+	}
+
+	// /embed returns one pooled vector per input
+	var pooled [][]float64
+	if err := json.Unmarshal(data, &pooled); err != nil {
+		return nil, err
+	}
+	tokens = make([][][]float64, len(pooled))
+	for i, vector := range pooled {
+		tokens[i] = [][]float64{vector}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,224 @@
+// Package stats provides the statistics used to summarize and compare eval runs
+package stats
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// Default bootstrap settings
+const (
+	DefaultResamples  = 10000
+	DefaultConfidence = 0.95
+)
+
+// BootstrapOptions configures bootstrap resampling
+// Resampling is seeded, so results are reproducible for the same inputs and options
+type BootstrapOptions struct {
+	// Resamples is the number of bootstrap resamples (default: 10000)
+	Resamples int
+	// Confidence is the confidence level of the interval (default: 0.95)
+	Confidence float64
+	// Seed seeds the random resampling (default: 0)
+	Seed uint64
+}
+
+func (o BootstrapOptions) withDefaults() BootstrapOptions {
+	if o.Resamples <= 0 {
+		o.Resamples = DefaultResamples
+	}
+	if o.Confidence <= 0 || o.Confidence >= 1 {
+		o.Confidence = DefaultConfidence
+	}
+	return o
+}
+
+// PairedBootstrapResult is the outcome of a paired bootstrap test
+type PairedBootstrapResult struct {
+	// MeanDelta is the mean of candidate - baseline
+	MeanDelta float64 `json:"mean_delta"`
+	// Low and High bound the confidence interval of MeanDelta
+	Low  float64 `json:"low"`
+	High float64 `json:"high"`
+	// PValue is the two-sided p-value of the null hypothesis that the mean delta is 0
+	PValue float64 `json:"p_value"`
+}
+
+// PairedBootstrap resamples paired differences candidate[i] - baseline[i] to estimate a confidence
+// interval of the mean difference and a p-value against no difference
+func PairedBootstrap(baseline, candidate []float64, opts BootstrapOptions) (PairedBootstrapResult, error) {
+	if len(baseline) != len(candidate) {
+		return PairedBootstrapResult{}, fmt.Errorf("paired samples have different lengths: %d and %d", len(baseline), len(candidate))
+	}
+	if len(baseline) == 0 {
+		return PairedBootstrapResult{}, fmt.Errorf("no paired samples")
+	}
+	opts = opts.withDefaults()
+
+	deltas := make([]float64, len(baseline))
+	for i := range baseline {
+		deltas[i] = candidate[i] - baseline[i]
+	}
+	observed := Mean(deltas)
+
+	means := resampleMeans(deltas, opts)
+	extreme := 0
+	for _, m := range means {
+		// Under the null hypothesis the resampled means are centered on 0 instead of the observed mean
+		if math.Abs(m-observed) >= math.Abs(observed)-1e-12 {
+			extreme++
+		}
+	}
+
+	interval := percentileInterval(means, opts.Confidence)
+	return PairedBootstrapResult{
+		MeanDelta: observed,
+		Low:       interval.Low,
+		High:      interval.High,
+		PValue:    float64(extreme) / float64(opts.Resamples),
+	}, nil
+}
+
+// resampleMeans returns the sorted means of opts.Resamples resamples of values with replacement
+func resampleMeans(values []float64, opts BootstrapOptions) []float64 {
+	rng := rand.New(rand.NewPCG(opts.Seed, 0))
+	means := make([]float64, opts.Resamples)
+	for r := range means {
+		var sum float64
+		for range values {
+			sum += values[rng.IntN(len(values))]
+		}
+		means[r] = sum / float64(len(values))
+	}
+	slices.Sort(means)
+	return means
+}
+
+// percentileInterval returns the central confidence interval of sorted bootstrap estimates
+func percentileInterval(sorted []float64, confidence float64) Interval {
+	alpha := 1 - confidence
+	return Interval{Low: percentile(sorted, alpha/2), High: percentile(sorted, 1-alpha/2)}
+}
+
+// WilcoxonResult is the outcome of a Wilcoxon signed-rank test
+type WilcoxonResult struct {
+	// Statistic is the sum of ranks of positive differences (W+)
+	Statistic float64 `json:"statistic"`
+	// N is the number of non-zero differences
+	N int `json:"n"`
+	// PValue is the two-sided p-value of the null hypothesis that the differences are symmetric around 0
+	PValue float64 `json:"p_value"`
+	// Exact is true when the p-value comes from the exact distribution rather than the normal approximation
+	Exact bool `json:"exact"`
+}
+
+// maxExactWilcoxon is the largest sample size for which the exact distribution is computed
+const maxExactWilcoxon = 30
+
+// WilcoxonSignedRank tests whether paired differences candidate[i] - baseline[i] are centered on 0
+// Zero differences are dropped. The exact distribution is used for small samples without ties,
+// otherwise the normal approximation with tie and continuity corrections
+func WilcoxonSignedRank(baseline, candidate []float64) (WilcoxonResult, error) {
+	if len(baseline) != len(candidate) {
+		return WilcoxonResult{}, fmt.Errorf("paired samples have different lengths: %d and %d", len(baseline), len(candidate))
+	}
+
+	var deltas []float64
+	for i := range baseline {
+		if d := candidate[i] - baseline[i]; d != 0 {
+			deltas = append(deltas, d)
+		}
+	}
+	n := len(deltas)
+	if n == 0 {
+		return WilcoxonResult{PValue: 1, Exact: true}, nil
+	}
+
+	// Rank absolute differences, averaging the ranks of ties
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(a, b int) int {
+		return cmp.Compare(math.Abs(deltas[a]), math.Abs(deltas[b]))
+	})
+	ranks := make([]float64, n)
+	var tieCorrection float64
+	ties := false
+	for start := 0; start < n; {
+		end := start + 1
+		for end < n && math.Abs(deltas[order[end]]) == math.Abs(deltas[order[start]]) {
+			end++
+		}
+		rank := float64(start+end+1) / 2
+		for _, index := range order[start:end] {
+			ranks[index] = rank
+		}
+		if t := float64(end - start); t > 1 {
+			ties = true
+			tieCorrection += t*t*t - t
+		}
+		start = end
+	}
+
+	var wPlus float64
+	for i, d := range deltas {
+		if d > 0 {
+			wPlus += ranks[i]
+		}
+	}
+	total := float64(n*(n+1)) / 2
+	result := WilcoxonResult{Statistic: wPlus, N: n}
+
+	if n <= maxExactWilcoxon && !ties {
+		result.Exact = true
+		result.PValue = exactWilcoxonPValue(n, int(math.Min(wPlus, total-wPlus)))
+		return result, nil
+	}
+
+	mu := total / 2
+	sigma := math.Sqrt(float64(n*(n+1)*(2*n+1))/24 - tieCorrection/48)
+	if sigma == 0 {
+		result.PValue = 1
+		return result, nil
+	}
+	z := (math.Abs(wPlus-mu) - 0.5) / sigma
+	result.PValue = math.Min(1, 2*(1-normalCDF(math.Max(z, 0))))
+	return result, nil
+}
+
+// exactWilcoxonPValue returns the two-sided p-value P(W <= w) * 2 for n untied differences
+func exactWilcoxonPValue(n, w int) float64 {
+	// counts[s] is the number of subsets of ranks 1..k summing to s
+	maxSum := n * (n + 1) / 2
+	counts := make([]float64, maxSum+1)
+	counts[0] = 1
+	for k := 1; k <= n; k++ {
+		for s := maxSum; s >= k; s-- {
+			counts[s] += counts[s-k]
+		}
+	}
+	var tail float64
+	for s := 0; s <= w; s++ {
+		tail += counts[s]
+	}
+	return math.Min(1, 2*tail/math.Pow(2, float64(n)))
+}
+
+func normalCDF(z float64) float64 {
+	return 0.5 * math.Erfc(-z/math.Sqrt2)
+}
+
+// percentile returns the q-quantile of sorted values with linear interpolation
+func percentile(sorted []float64, q float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	pos := q * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := min(lower+1, len(sorted)-1)
+	return sorted[lower] + (pos-float64(lower))*(sorted[upper]-sorted[lower])
+}
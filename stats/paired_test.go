@@ -0,0 +1,178 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPairedBootstrap(t *testing.T) {
+	tests := []struct {
+		name      string
+		baseline  []float64
+		candidate []float64
+		wantMean  float64
+		wantLow   float64
+		wantHigh  float64
+		wantPMin  float64
+		wantPMax  float64
+	}{
+		{
+			name:      "constant improvement",
+			baseline:  []float64{0.2, 0.4, 0.6},
+			candidate: []float64{0.7, 0.9, 1.1},
+			wantMean:  0.5,
+			wantLow:   0.5,
+			wantHigh:  0.5,
+			wantPMin:  0,
+			wantPMax:  0,
+		},
+		{
+			name:      "no change",
+			baseline:  []float64{0.5, 0.5},
+			candidate: []float64{0.5, 0.5},
+			wantPMin:  1,
+			wantPMax:  1,
+		},
+		{
+			name:      "symmetric noise",
+			baseline:  []float64{0.5, 0.5, 0.5, 0.5, 0.5, 0.5},
+			candidate: []float64{0.6, 0.4, 0.7, 0.3, 0.55, 0.45},
+			wantLow:   -0.2,
+			wantHigh:  0.2,
+			wantPMin:  0.5,
+			wantPMax:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PairedBootstrap(tt.baseline, tt.candidate, BootstrapOptions{Resamples: 2000})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got.MeanDelta-tt.wantMean) > 1e-9 {
+				t.Errorf("MeanDelta = %v, want %v", got.MeanDelta, tt.wantMean)
+			}
+			if got.Low < tt.wantLow-1e-9 || got.High > tt.wantHigh+1e-9 || got.Low > got.MeanDelta || got.High < got.MeanDelta {
+				t.Errorf("interval = [%v, %v], want within [%v, %v] around %v", got.Low, got.High, tt.wantLow, tt.wantHigh, got.MeanDelta)
+			}
+			if got.PValue < tt.wantPMin || got.PValue > tt.wantPMax {
+				t.Errorf("PValue = %v, want in [%v, %v]", got.PValue, tt.wantPMin, tt.wantPMax)
+			}
+		})
+	}
+}
+
+func TestPairedBootstrap_Deterministic(t *testing.T) {
+	baseline := []float64{0.1, 0.5, 0.9, 0.3, 0.7}
+	candidate := []float64{0.2, 0.4, 1.0, 0.6, 0.7}
+	first, err := PairedBootstrap(baseline, candidate, BootstrapOptions{Seed: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := PairedBootstrap(baseline, candidate, BootstrapOptions{Seed: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Errorf("same seed gave %+v and %+v", first, second)
+	}
+}
+
+func TestPairedBootstrap_Errors(t *testing.T) {
+	if _, err := PairedBootstrap([]float64{1}, []float64{1, 2}, BootstrapOptions{}); err == nil {
+		t.Error("expected error for different lengths")
+	}
+	if _, err := PairedBootstrap(nil, nil, BootstrapOptions{}); err == nil {
+		t.Error("expected error for no samples")
+	}
+}
+
+func TestWilcoxonSignedRank(t *testing.T) {
+	tests := []struct {
+		name      string
+		baseline  []float64
+		candidate []float64
+		wantW     float64
+		wantN     int
+		wantP     float64
+		wantExact bool
+	}{
+		{
+			name:      "all improved",
+			baseline:  []float64{0, 0, 0, 0, 0},
+			candidate: []float64{1, 2, 3, 4, 5},
+			wantW:     15,
+			wantN:     5,
+			wantP:     2.0 / 32,
+			wantExact: true,
+		},
+		{
+			name:      "one small regression",
+			baseline:  []float64{1, 0, 0, 0, 0},
+			candidate: []float64{0, 2, 3, 4, 5},
+			wantW:     14,
+			wantN:     5,
+			wantP:     4.0 / 32,
+			wantExact: true,
+		},
+		{
+			name:      "zero differences are dropped",
+			baseline:  []float64{0.5, 0.5, 0, 0},
+			candidate: []float64{0.5, 0.5, 1, -2},
+			wantW:     1,
+			wantN:     2,
+			wantP:     1,
+			wantExact: true,
+		},
+		{
+			name:      "no differences",
+			baseline:  []float64{0.3, 0.7},
+			candidate: []float64{0.3, 0.7},
+			wantP:     1,
+			wantExact: true,
+		},
+		{
+			// Ranks 1.5, 1.5, 3..6 with W+ = 19.5; mu = 10.5, sigma = sqrt(22.75 - 6/48)
+			name:      "ties use the normal approximation",
+			baseline:  []float64{0, 0, 0, 0, 0, 0},
+			candidate: []float64{1, -1, 2, 3, 4, 5},
+			wantW:     19.5,
+			wantN:     6,
+			wantP:     2 * (1 - normalCDF((9-0.5)/math.Sqrt(22.625))),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WilcoxonSignedRank(tt.baseline, tt.candidate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Statistic != tt.wantW || got.N != tt.wantN || got.Exact != tt.wantExact {
+				t.Errorf("got W=%v N=%d exact=%v, want W=%v N=%d exact=%v", got.Statistic, got.N, got.Exact, tt.wantW, tt.wantN, tt.wantExact)
+			}
+			if math.Abs(got.PValue-tt.wantP) > 1e-9 {
+				t.Errorf("PValue = %v, want %v", got.PValue, tt.wantP)
+			}
+		})
+	}
+}
+
+func TestWilcoxonSignedRank_LargeSample(t *testing.T) {
+	baseline := make([]float64, 50)
+	candidate := make([]float64, 50)
+	for i := range candidate {
+		candidate[i] = float64(i + 1)
+	}
+	got, err := WilcoxonSignedRank(baseline, candidate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Exact {
+		t.Error("expected the normal approximation for 50 differences")
+	}
+	if got.PValue > 1e-6 {
+		t.Errorf("PValue = %v, want a tiny p-value when every row improves", got.PValue)
+	}
+}
@@ -0,0 +1,175 @@
+package stats
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+// DefaultBins is the default number of histogram bins
+const DefaultBins = 10
+
+// Interval is a confidence interval
+type Interval struct {
+	Low  float64 `json:"low"`
+	High float64 `json:"high"`
+}
+
+// Bin is one histogram bin covering [Low, High); the last bin also includes High
+type Bin struct {
+	Low   float64 `json:"low"`
+	High  float64 `json:"high"`
+	Count int     `json:"count"`
+}
+
+// SummaryOptions configures Summarize
+type SummaryOptions struct {
+	// Bootstrap configures the confidence interval of the mean
+	Bootstrap BootstrapOptions
+	// Bins is the number of equal-width histogram bins over [0, 1] (default: 10)
+	Bins int
+	// ExcludeErrors leaves errored scores out of the statistics instead of counting them as 0
+	ExcludeErrors bool
+}
+
+// Summary describes a set of scores
+type Summary struct {
+	// N is the number of scores, including errored ones
+	N         int      `json:"n"`
+	Errors    int      `json:"errors"`
+	ErrorRate float64  `json:"error_rate"`
+	Mean      float64  `json:"mean"`
+	StdDev    float64  `json:"std_dev"`
+	StdErr    float64  `json:"std_err"`
+	Min       float64  `json:"min"`
+	Median    float64  `json:"median"`
+	Max       float64  `json:"max"`
+	CI        Interval `json:"ci"`
+	Histogram []Bin    `json:"histogram"`
+}
+
+// Summarize computes the mean with its standard error and bootstrap confidence interval, the spread,
+// a histogram and the error rate of scores
+// Errored scores count as 0, as in the scorers' own results, unless ExcludeErrors is set
+func Summarize(scores []api.Score, opts SummaryOptions) Summary {
+	summary := Summary{N: len(scores)}
+	values := make([]float64, 0, len(scores))
+	for _, score := range scores {
+		if score.Error != nil {
+			summary.Errors++
+			if opts.ExcludeErrors {
+				continue
+			}
+		}
+		values = append(values, score.Score)
+	}
+	if summary.N > 0 {
+		summary.ErrorRate = float64(summary.Errors) / float64(summary.N)
+	}
+	summary.Histogram = Histogram(values, opts.Bins)
+	if len(values) == 0 {
+		return summary
+	}
+
+	sorted := slices.Sorted(slices.Values(values))
+	summary.Mean = Mean(values)
+	summary.StdDev = StdDev(values)
+	summary.StdErr = StandardError(values)
+	summary.Min, summary.Max = sorted[0], sorted[len(sorted)-1]
+	summary.Median = percentile(sorted, 0.5)
+	summary.CI, _ = BootstrapMean(values, opts.Bootstrap)
+	return summary
+}
+
+// Mean returns the arithmetic mean of values, or 0 when there are none
+func Mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// StdDev returns the sample standard deviation of values, or 0 for fewer than two values
+func StdDev(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	m := Mean(values)
+	var squares float64
+	for _, v := range values {
+		squares += (v - m) * (v - m)
+	}
+	return math.Sqrt(squares / float64(len(values)-1))
+}
+
+// StandardError returns the standard error of the mean of values
+func StandardError(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	return StdDev(values) / math.Sqrt(float64(len(values)))
+}
+
+// BootstrapMean returns the percentile bootstrap confidence interval of the mean of values
+func BootstrapMean(values []float64, opts BootstrapOptions) (Interval, error) {
+	if len(values) == 0 {
+		return Interval{}, fmt.Errorf("no samples")
+	}
+	opts = opts.withDefaults()
+	return percentileInterval(resampleMeans(values, opts), opts.Confidence), nil
+}
+
+// Histogram counts values in equal-width bins over [0, 1] (default: 10 bins)
+// Values outside the range are counted in the first or last bin
+func Histogram(values []float64, bins int) []Bin {
+	if bins <= 0 {
+		bins = DefaultBins
+	}
+	histogram := make([]Bin, bins)
+	for i := range histogram {
+		histogram[i].Low = float64(i) / float64(bins)
+		histogram[i].High = float64(i+1) / float64(bins)
+	}
+	for _, v := range values {
+		index := int(math.Floor(v * float64(bins)))
+		histogram[max(0, min(index, bins-1))].Count++
+	}
+	return histogram
+}
+
+// TaggedScore is a score together with the tags of the row it was computed on
+type TaggedScore struct {
+	Score api.Score
+	Tags  map[string]string
+}
+
+// Stratum summarizes the scores of rows sharing one value of a tag
+type Stratum struct {
+	// Value is the tag value; rows without the tag are grouped under ""
+	Value   string  `json:"value"`
+	Summary Summary `json:"summary"`
+}
+
+// SummarizeBy groups scores by the value of a row tag, such as a category or language, and
+// summarizes each group, ordered by tag value
+func SummarizeBy(scores []TaggedScore, tag string, opts SummaryOptions) []Stratum {
+	groups := make(map[string][]api.Score)
+	for _, score := range scores {
+		value := score.Tags[tag]
+		groups[value] = append(groups[value], score.Score)
+	}
+
+	strata := make([]Stratum, 0, len(groups))
+	for value, group := range groups {
+		strata = append(strata, Stratum{Value: value, Summary: Summarize(group, opts)})
+	}
+	slices.SortFunc(strata, func(a, b Stratum) int { return cmp.Compare(a.Value, b.Value) })
+	return strata
+}
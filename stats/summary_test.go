@@ -0,0 +1,187 @@
+package stats
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/datar-psa/goeval/api"
+)
+
+func scores(values ...float64) []api.Score {
+	out := make([]api.Score, len(values))
+	for i, v := range values {
+		out[i] = api.Score{Name: "test", Score: v}
+	}
+	return out
+}
+
+func TestSummarize(t *testing.T) {
+	failed := api.Score{Name: "test", Error: errors.New("timeout")}
+
+	tests := []struct {
+		name          string
+		scores        []api.Score
+		opts          SummaryOptions
+		wantN         int
+		wantErrors    int
+		wantErrorRate float64
+		wantMean      float64
+		wantStdDev    float64
+		wantStdErr    float64
+		wantMin       float64
+		wantMedian    float64
+		wantMax       float64
+	}{
+		{
+			name:       "scores",
+			scores:     scores(0.2, 0.4, 0.6, 0.8),
+			wantN:      4,
+			wantMean:   0.5,
+			wantStdDev: math.Sqrt(0.2 / 3),
+			wantStdErr: math.Sqrt(0.2/3) / 2,
+			wantMin:    0.2,
+			wantMedian: 0.5,
+			wantMax:    0.8,
+		},
+		{
+			name:          "errors count as zero",
+			scores:        append(scores(1, 1, 1), failed),
+			wantN:         4,
+			wantErrors:    1,
+			wantErrorRate: 0.25,
+			wantMean:      0.75,
+			wantStdDev:    0.5,
+			wantStdErr:    0.25,
+			wantMin:       0,
+			wantMedian:    1,
+			wantMax:       1,
+		},
+		{
+			name:          "errors excluded",
+			scores:        append(scores(1, 1, 1), failed),
+			opts:          SummaryOptions{ExcludeErrors: true},
+			wantN:         4,
+			wantErrors:    1,
+			wantErrorRate: 0.25,
+			wantMean:      1,
+			wantMin:       1,
+			wantMedian:    1,
+			wantMax:       1,
+		},
+		{
+			name:          "only errors",
+			scores:        []api.Score{failed},
+			opts:          SummaryOptions{ExcludeErrors: true},
+			wantN:         1,
+			wantErrors:    1,
+			wantErrorRate: 1,
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Summarize(tt.scores, tt.opts)
+			if got.N != tt.wantN || got.Errors != tt.wantErrors {
+				t.Errorf("N = %d, Errors = %d, want %d and %d", got.N, got.Errors, tt.wantN, tt.wantErrors)
+			}
+			for _, check := range []struct {
+				name      string
+				got, want float64
+			}{
+				{"ErrorRate", got.ErrorRate, tt.wantErrorRate},
+				{"Mean", got.Mean, tt.wantMean},
+				{"StdDev", got.StdDev, tt.wantStdDev},
+				{"StdErr", got.StdErr, tt.wantStdErr},
+				{"Min", got.Min, tt.wantMin},
+				{"Median", got.Median, tt.wantMedian},
+				{"Max", got.Max, tt.wantMax},
+			} {
+				if math.Abs(check.got-check.want) > 1e-9 {
+					t.Errorf("%s = %v, want %v", check.name, check.got, check.want)
+				}
+			}
+			if got.CI.Low > got.Mean || got.CI.High < got.Mean {
+				t.Errorf("CI = %+v does not contain the mean %v", got.CI, got.Mean)
+			}
+			if len(got.Histogram) != DefaultBins {
+				t.Errorf("got %d bins, want %d", len(got.Histogram), DefaultBins)
+			}
+		})
+	}
+}
+
+func TestBootstrapMean(t *testing.T) {
+	values := make([]float64, 100)
+	for i := range values {
+		values[i] = float64(i%2) * 0.8
+	}
+	got, err := BootstrapMean(values, BootstrapOptions{Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The mean is 0.4 with a standard error of about 0.04, so the 95% interval spans about ±0.08
+	if got.Low < 0.3 || got.Low > 0.35 || got.High < 0.45 || got.High > 0.5 {
+		t.Errorf("interval = %+v, want about [0.32, 0.48]", got)
+	}
+
+	narrow, err := BootstrapMean(values, BootstrapOptions{Seed: 1, Confidence: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if narrow.Low <= got.Low || narrow.High >= got.High {
+		t.Errorf("50%% interval %+v should be inside the 95%% interval %+v", narrow, got)
+	}
+
+	if _, err := BootstrapMean(nil, BootstrapOptions{}); err == nil {
+		t.Error("expected error for no samples")
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	got := Histogram([]float64{0, 0.1, 0.49, 0.5, 1, 1.2, -0.1}, 4)
+	want := []Bin{{0, 0.25, 3}, {0.25, 0.5, 1}, {0.5, 0.75, 1}, {0.75, 1, 2}}
+	if len(got) != len(want) {
+		t.Fatalf("got %d bins, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("bin %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSummarizeBy(t *testing.T) {
+	tagged := []TaggedScore{
+		{Score: api.Score{Score: 1}, Tags: map[string]string{"language": "en"}},
+		{Score: api.Score{Score: 0.5}, Tags: map[string]string{"language": "de"}},
+		{Score: api.Score{Score: 0}, Tags: map[string]string{"language": "en"}},
+		{Score: api.Score{Error: errors.New("failed")}, Tags: map[string]string{"language": "de"}},
+		{Score: api.Score{Score: 0.3}},
+	}
+
+	got := SummarizeBy(tagged, "language", SummaryOptions{})
+	want := []struct {
+		value  string
+		n      int
+		errors int
+		mean   float64
+	}{
+		{"", 1, 0, 0.3},
+		{"de", 2, 1, 0.25},
+		{"en", 2, 0, 0.5},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d strata, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		s := got[i]
+		if s.Value != w.value || s.Summary.N != w.n || s.Summary.Errors != w.errors || math.Abs(s.Summary.Mean-w.mean) > 1e-9 {
+			t.Errorf("stratum %d = %q n=%d errors=%d mean=%v, want %q n=%d errors=%d mean=%v",
+				i, s.Value, s.Summary.N, s.Summary.Errors, s.Summary.Mean, w.value, w.n, w.errors, w.mean)
+		}
+	}
+}